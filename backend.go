@@ -0,0 +1,57 @@
+package easyq
+
+import (
+	"sync"
+
+	"github.com/Henrikarba/easyq-go/bridge"
+	"github.com/Henrikarba/easyq-go/simulator"
+)
+
+// BackendFactory returns the bridge.Backend implementation to use for a backend type.
+type BackendFactory func() bridge.Backend
+
+var (
+	backendsMutex sync.RWMutex
+	backends      = make(map[QuantumBackendType]BackendFactory)
+)
+
+func init() {
+	// Simulation runs on the pure-Go state-vector simulator. Hardware backends
+	// are provided by the native bridge when built with the easyq_native tag.
+	RegisterBackend(Simulator, func() bridge.Backend {
+		return simulator.NewBackend()
+	})
+}
+
+// RegisterBackend makes a backend implementation available for the given backend type.
+// Registering a factory for a type that already has one replaces the previous factory.
+// It panics if factory is nil.
+//
+// Example:
+//
+//	easyq.RegisterBackend(easyq.CustomQuantumBackend, func() bridge.Backend {
+//		return myBackend
+//	})
+func RegisterBackend(backendType QuantumBackendType, factory BackendFactory) {
+	if factory == nil {
+		panic("easyq: RegisterBackend factory is nil")
+	}
+
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+
+	backends[backendType] = factory
+}
+
+// newBackend creates the registered backend for the given backend type
+func newBackend(backendType QuantumBackendType) (bridge.Backend, error) {
+	backendsMutex.RLock()
+	factory, ok := backends[backendType]
+	backendsMutex.RUnlock()
+
+	if !ok {
+		return nil, ErrBackendNotRegistered
+	}
+
+	return factory(), nil
+}
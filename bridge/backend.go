@@ -0,0 +1,71 @@
+package bridge
+
+import "context"
+
+// Backend is an implementation of the quantum operations exposed by the bridge.
+//
+// Values exchanged with a Backend follow the same conventions as the native
+// library: configs and options are JSON-serializable values, and results are
+// the decoded JSON documents described in bridge.h. This keeps every
+// implementation interchangeable with the cgo bridge.
+//
+// Implementations must be safe for concurrent use, and should return promptly
+// with ctx.Err() once ctx is done where the underlying operation allows it.
+type Backend interface {
+	// Initialize prepares the backend for use.
+	Initialize() error
+
+	// Shutdown releases any resources held by the backend.
+	Shutdown()
+
+	// ConfigureConnection configures the connection to a quantum computing resource.
+	ConfigureConnection(ctx context.Context, config interface{}) error
+
+	// Search performs a quantum search using Grover's algorithm.
+	Search(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, error)
+
+	// GenerateRandomInt generates a random integer between min and max (inclusive).
+	GenerateRandomInt(ctx context.Context, min, max int) (int, error)
+
+	// GenerateRandomBytes generates length random bytes.
+	GenerateRandomBytes(ctx context.Context, length int) ([]byte, error)
+
+	// GenerateKey generates a key using quantum key distribution.
+	GenerateKey(ctx context.Context, options interface{}) (map[string]interface{}, error)
+}
+
+// SearchReporter is implemented by backends that report statistics about
+// a search, such as the number of Grover iterations that ran.
+type SearchReporter interface {
+	// SearchWithReport performs a quantum search like Backend.Search, and also
+	// returns a report document with statistics about the run, as described
+	// in bridge.h.
+	SearchWithReport(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, map[string]interface{}, error)
+}
+
+// Counter is implemented by backends that can estimate the number of items
+// matching a predicate by quantum counting.
+type Counter interface {
+	// Count estimates the number of items matching the predicate. The result
+	// document is described in bridge.h.
+	Count(ctx context.Context, items interface{}, predicate interface{}, options interface{}) (map[string]interface{}, error)
+}
+
+// SpaceSearcher is implemented by backends that can search an implicit space
+// of integers without the items being passed to them. Since the oracle is a Go
+// function, only backends running in the same process can implement it.
+type SpaceSearcher interface {
+	// SearchSpace performs a quantum search over the integers [0, 2^qubits)
+	// for values accepted by oracle. Results and report are as for
+	// SearchReporter.SearchWithReport, with the value found as the Index and
+	// Item of each result.
+	SearchSpace(ctx context.Context, qubits int, oracle func(uint64) bool, options interface{}) ([]interface{}, map[string]interface{}, error)
+}
+
+// CapacityReporter is implemented by backends with a known limit on the size of
+// the quantum register they can run.
+type CapacityReporter interface {
+	// QubitCapacity returns the largest number of qubits the backend can use
+	// for a search register.
+	QubitCapacity() int
+}
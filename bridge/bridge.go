@@ -1,21 +1,14 @@
-// Package bridge provides direct communication with the native quantum operations
-// via a DLL/shared library interface.
+// Package bridge provides direct communication with the quantum operations
+// implemented by a Backend, such as the native DLL/shared library interface.
+// Every operation reports failures as an *Error carrying the status code.
+//
+// The cgo binding to the native library is only compiled when building with
+// the easyq_native build tag. Default builds do not require cgo.
 package bridge
 
-// #cgo windows LDFLAGS: -L${SRCDIR}/../../lib/windows_amd64 -lEasyQBridge
-// #cgo linux LDFLAGS: -L${SRCDIR}/../../lib/linux_amd64 -lEasyQBridge
-// #cgo darwin LDFLAGS: -L${SRCDIR}/../../lib/darwin_amd64 -lEasyQBridge
-// #include <stdlib.h>
-// #include <stdint.h>
-// #include "bridge.h"
-
 import (
-	"C"
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
 	"sync"
-	"unsafe"
 )
 
 // Status codes from the DLL
@@ -28,206 +21,211 @@ const (
 	StatusErrorTimeout         = 5
 )
 
-var (
-	bridgeMutex   sync.Mutex
-	isInitialized bool
-)
+// Client runs operations on a single Backend.
+//
+// A Client is safe for concurrent use, and operations run concurrently with
+// each other: the Backend is responsible for any synchronization it needs.
+// Initialize and Shutdown wait for operations in flight to finish.
+type Client struct {
+	backend Backend
+
+	mu          sync.RWMutex
+	initialized bool
+}
 
-// Initialize initializes the quantum bridge.
-func Initialize() error {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
+// NewClient returns a Client for the given backend.
+// The client must be initialized before use.
+func NewClient(backend Backend) *Client {
+	return &Client{backend: backend}
+}
+
+// Backend returns the backend the client runs operations on.
+func (c *Client) Backend() Backend {
+	return c.backend
+}
 
-	if isInitialized {
+// QubitCapacity returns the largest number of qubits the backend can use for a
+// search register, or 0 if the backend does not implement CapacityReporter.
+func (c *Client) QubitCapacity() int {
+	if reporter, ok := c.backend.(CapacityReporter); ok {
+		return reporter.QubitCapacity()
+	}
+	return 0
+}
+
+// Initialize initializes the client's backend.
+func (c *Client) Initialize() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
 		return nil
 	}
 
-	result := C.EasyQ_Initialize()
-	if result != StatusSuccess {
-		return fmt.Errorf("failed to initialize quantum bridge: error code %d", result)
+	if c.backend == nil {
+		return NewError("Initialize", StatusErrorNotInitialized, "no backend selected")
 	}
 
-	isInitialized = true
+	if err := c.backend.Initialize(); err != nil {
+		return wrapError("Initialize", err)
+	}
+
+	c.initialized = true
 	return nil
 }
 
-// Shutdown cleans up resources used by the quantum bridge.
-func Shutdown() {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
+// Shutdown cleans up resources used by the client's backend.
+func (c *Client) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if isInitialized {
-		C.EasyQ_Shutdown()
-		isInitialized = false
+	if c.initialized {
+		c.backend.Shutdown()
+		c.initialized = false
 	}
 }
 
-// ConfigureConnection configures the connection to a quantum computing resource.
-func ConfigureConnection(config interface{}) error {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
-
-	if !isInitialized {
-		return errors.New("bridge not initialized")
+// run calls fn with the backend and converts any failure into an *Error for op.
+// If ctx is done first, run fails with ctx.Err() without waiting for fn: native
+// calls cannot be interrupted, so fn keeps running in the background and
+// holds off Shutdown until it returns.
+func (c *Client) run(ctx context.Context, op string, fn func(b Backend) error) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError(op, err)
 	}
 
-	// Convert the config to JSON
-	configJSON, err := json.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal connection config: %w", err)
+	c.mu.RLock()
+	if !c.initialized {
+		c.mu.RUnlock()
+		return NewError(op, StatusErrorNotInitialized, "bridge not initialized")
 	}
 
-	// Convert JSON to C string
-	cConfigJSON := C.CString(string(configJSON))
-	defer C.free(unsafe.Pointer(cConfigJSON))
+	done := make(chan error, 1)
+	go func() {
+		defer c.mu.RUnlock()
+		done <- fn(c.backend)
+	}()
 
-	// Call the DLL function
-	result := C.EasyQ_ConfigureConnection(cConfigJSON)
-	if result != StatusSuccess {
-		return fmt.Errorf("failed to configure quantum connection: error code %d", result)
+	select {
+	case err := <-done:
+		return wrapError(op, err)
+	case <-ctx.Done():
+		return wrapError(op, ctx.Err())
 	}
+}
 
-	return nil
+// ConfigureConnection configures the connection to a quantum computing resource.
+func (c *Client) ConfigureConnection(ctx context.Context, config interface{}) error {
+	return c.run(ctx, "ConfigureConnection", func(b Backend) error {
+		return b.ConfigureConnection(ctx, config)
+	})
 }
 
 // Search performs a quantum search using Grover's algorithm.
-func Search(items interface{}, predicate interface{}, options interface{}) ([]interface{}, error) {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
-
-	if !isInitialized {
-		return nil, errors.New("bridge not initialized")
-	}
-
-	// Convert parameters to JSON
-	itemsJSON, err := json.Marshal(items)
+func (c *Client) Search(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, error) {
+	var results []interface{}
+	err := c.run(ctx, "Search", func(b Backend) (err error) {
+		results, err = b.Search(ctx, items, predicate, options)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal items: %w", err)
+		return nil, err
 	}
+	return results, nil
+}
 
-	predicateJSON, err := json.Marshal(predicate)
+// SearchWithReport performs a quantum search like Search, and also returns a
+// report document with statistics about the run. The report is nil if the
+// backend does not implement SearchReporter.
+func (c *Client) SearchWithReport(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, map[string]interface{}, error) {
+	var results []interface{}
+	var report map[string]interface{}
+	err := c.run(ctx, "Search", func(b Backend) (err error) {
+		if reporter, ok := b.(SearchReporter); ok {
+			results, report, err = reporter.SearchWithReport(ctx, items, predicate, options)
+		} else {
+			results, err = b.Search(ctx, items, predicate, options)
+		}
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal predicate: %w", err)
+		return nil, nil, err
 	}
+	return results, report, nil
+}
 
-	optionsJSON, err := json.Marshal(options)
+// Count estimates the number of items matching the predicate by quantum counting.
+// It fails with StatusErrorGeneral if the backend does not implement Counter.
+func (c *Client) Count(ctx context.Context, items interface{}, predicate interface{}, options interface{}) (map[string]interface{}, error) {
+	var countResult map[string]interface{}
+	err := c.run(ctx, "Count", func(b Backend) (err error) {
+		counter, ok := b.(Counter)
+		if !ok {
+			return NewError("", StatusErrorGeneral, "backend does not support quantum counting")
+		}
+		countResult, err = counter.Count(ctx, items, predicate, options)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal options: %w", err)
+		return nil, err
 	}
+	return countResult, nil
+}
 
-	// Convert JSON to C strings
-	cItemsJSON := C.CString(string(itemsJSON))
-	defer C.free(unsafe.Pointer(cItemsJSON))
-
-	cPredicateJSON := C.CString(string(predicateJSON))
-	defer C.free(unsafe.Pointer(cPredicateJSON))
-
-	cOptionsJSON := C.CString(string(optionsJSON))
-	defer C.free(unsafe.Pointer(cOptionsJSON))
-
-	// Prepare for result
-	var cResultJSON *C.char
-
-	// Call the DLL function
-	result := C.EasyQ_Search(cItemsJSON, cPredicateJSON, cOptionsJSON, &cResultJSON)
-	if result != StatusSuccess {
-		return nil, fmt.Errorf("quantum search failed: error code %d", result)
-	}
-
-	// Convert result back to Go and free the C string
-	goResultJSON := C.GoString(cResultJSON)
-	C.EasyQ_FreeString(cResultJSON)
-
-	// Unmarshal the result
-	var searchResults []interface{}
-	err = json.Unmarshal([]byte(goResultJSON), &searchResults)
+// SearchSpace performs a quantum search over the integers [0, 2^qubits).
+// It fails with StatusErrorGeneral if the backend does not implement SpaceSearcher.
+func (c *Client) SearchSpace(ctx context.Context, qubits int, oracle func(uint64) bool, options interface{}) ([]interface{}, map[string]interface{}, error) {
+	var results []interface{}
+	var report map[string]interface{}
+	err := c.run(ctx, "SearchSpace", func(b Backend) (err error) {
+		searcher, ok := b.(SpaceSearcher)
+		if !ok {
+			return NewError("", StatusErrorGeneral, "backend does not support searching implicit spaces")
+		}
+		results, report, err = searcher.SearchSpace(ctx, qubits, oracle, options)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal search results: %w", err)
+		return nil, nil, err
 	}
-
-	return searchResults, nil
+	return results, report, nil
 }
 
 // GenerateRandomInt generates a random integer using quantum measurement.
-func GenerateRandomInt(min, max int) (int, error) {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
-
-	if !isInitialized {
-		return 0, errors.New("bridge not initialized")
-	}
-
-	// Prepare for result
-	var result C.int
-
-	// Call the DLL function
-	status := C.EasyQ_GenerateRandomInt(C.int(min), C.int(max), &result)
-	if status != StatusSuccess {
-		return 0, fmt.Errorf("quantum RNG failed: error code %d", status)
+func (c *Client) GenerateRandomInt(ctx context.Context, min, max int) (int, error) {
+	var result int
+	err := c.run(ctx, "GenerateRandomInt", func(b Backend) (err error) {
+		result, err = b.GenerateRandomInt(ctx, min, max)
+		return err
+	})
+	if err != nil {
+		return 0, err
 	}
-
-	return int(result), nil
+	return result, nil
 }
 
 // GenerateRandomBytes generates random bytes using quantum measurement.
-func GenerateRandomBytes(length int) ([]byte, error) {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
-
-	if !isInitialized {
-		return nil, errors.New("bridge not initialized")
-	}
-
-	// Allocate a buffer for the result
-	buffer := make([]byte, length)
-
-	// Call the DLL function
-	status := C.EasyQ_GenerateRandomBytes(C.int(length), (*C.uchar)(unsafe.Pointer(&buffer[0])))
-	if status != StatusSuccess {
-		return nil, fmt.Errorf("quantum random bytes generation failed: error code %d", status)
+func (c *Client) GenerateRandomBytes(ctx context.Context, length int) ([]byte, error) {
+	var buffer []byte
+	err := c.run(ctx, "GenerateRandomBytes", func(b Backend) (err error) {
+		buffer, err = b.GenerateRandomBytes(ctx, length)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return buffer, nil
 }
 
 // GenerateKey generates a key using quantum key distribution.
-func GenerateKey(options interface{}) (map[string]interface{}, error) {
-	bridgeMutex.Lock()
-	defer bridgeMutex.Unlock()
-
-	if !isInitialized {
-		return nil, errors.New("bridge not initialized")
-	}
-
-	// Convert options to JSON
-	optionsJSON, err := json.Marshal(options)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal options: %w", err)
-	}
-
-	// Convert JSON to C string
-	cOptionsJSON := C.CString(string(optionsJSON))
-	defer C.free(unsafe.Pointer(cOptionsJSON))
-
-	// Prepare for result
-	var cResultJSON *C.char
-
-	// Call the DLL function
-	status := C.EasyQ_GenerateKey(cOptionsJSON, &cResultJSON)
-	if status != StatusSuccess {
-		return nil, fmt.Errorf("quantum key distribution failed: error code %d", status)
-	}
-
-	// Convert result back to Go and free the C string
-	goResultJSON := C.GoString(cResultJSON)
-	C.EasyQ_FreeString(cResultJSON)
-
-	// Unmarshal the result
+func (c *Client) GenerateKey(ctx context.Context, options interface{}) (map[string]interface{}, error) {
 	var keyResult map[string]interface{}
-	err = json.Unmarshal([]byte(goResultJSON), &keyResult)
+	err := c.run(ctx, "GenerateKey", func(b Backend) (err error) {
+		keyResult, err = b.GenerateKey(ctx, options)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal key distribution result: %w", err)
+		return nil, err
 	}
-
 	return keyResult, nil
 }
@@ -5,13 +5,34 @@
 extern "C" {
 #endif
 
+/* ABI version, checked by the host before any other call */
+int EasyQ_GetVersion(int* major, int* minor);
+
 /* Basic functions */
 int EasyQ_Initialize();
 void EasyQ_Shutdown();
 int EasyQ_ConfigureConnection(const char* config_json);
 void EasyQ_FreeString(char* str);
 
-/* Quantum Search */
+/* Error reporting (ABI 1.1+)
+ * Retrieves the message of the last failed call made on the calling thread.
+ * The message is thread-local, so concurrent calls on other threads do not
+ * overwrite it. The returned string must be released with EasyQ_FreeString. */
+int EasyQ_GetLastError(char** message);
+
+/* Quantum Search
+ * predicate_json is an object whose MarkedIndices array holds the indices of
+ * the items that satisfy the predicate, evaluated by the host. The oracle must
+ * mark exactly these indices. For hardware backends, the host also sends them
+ * compiled as a reversible phase-oracle circuit in Oracle: an object with the
+ * register width (Qubits), the number of ancillas (Ancillas) and a list of
+ * multi-controlled X and Z gates (Gates).
+ *
+ * result_json is either an array of results, each with an Index, the Item and
+ * optionally the Probability of measuring it, or an object holding that array
+ * in Results together with a Report object of run statistics: Iterations,
+ * EstimatedMatches, Attempts, OracleCalls, Qubits, SuccessProbability and the
+ * IterationStrategy and SamplingStrategy used. */
 int EasyQ_Search(
     const char* items_json, 
     const char* predicate_json,
@@ -19,6 +40,19 @@ int EasyQ_Search(
     char** result_json
 );
 
+/* Quantum Counting (ABI 1.2+)
+ * Estimates the number of items matching the predicate by phase estimation on
+ * the Grover operator. items_json and predicate_json are as for EasyQ_Search;
+ * options_json holds PrecisionBits, the number of precision qubits (0 for a
+ * default). result_json is an object with the Estimate, its Lower and Upper
+ * bounds, their Confidence, and the PrecisionBits, Qubits and OracleCalls used. */
+int EasyQ_Count(
+    const char* items_json,
+    const char* predicate_json,
+    const char* options_json,
+    char** result_json
+);
+
 /* Quantum Random Number Generation */
 int EasyQ_GenerateRandomInt(int min, int max, int* result);
 int EasyQ_GenerateRandomBytes(int length, unsigned char* buffer);
@@ -29,6 +63,10 @@ int EasyQ_GenerateKey(
     char** result_json
 );
 
+/* ABI version implemented by this header */
+#define EASYQ_ABI_VERSION_MAJOR 1
+#define EASYQ_ABI_VERSION_MINOR 2
+
 /* Error codes */
 #define EASYQ_SUCCESS 0
 #define EASYQ_ERROR_GENERAL 1
@@ -0,0 +1,137 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Error is returned by every bridge operation that fails.
+// It carries the status code reported by the backend, so callers can tell
+// invalid-argument failures apart from transient runtime faults:
+//
+//	if errors.Is(err, bridge.ErrInvalidArgument) {
+//		// don't retry
+//	}
+type Error struct {
+	// Op is the bridge operation that failed, such as "Search"
+	Op string
+
+	// Code is the status code of the failure (one of the Status* constants)
+	Code int
+
+	// Message is the error message reported by the backend, if any
+	Message string
+
+	// Err is the underlying cause, if any
+	Err error
+}
+
+// Sentinel errors for each failure status code, for use with errors.Is.
+// An *Error matches the sentinel with the same Code.
+var (
+	// ErrGeneral matches failures with StatusErrorGeneral
+	ErrGeneral = &Error{Code: StatusErrorGeneral}
+
+	// ErrNotInitialized matches failures with StatusErrorNotInitialized
+	ErrNotInitialized = &Error{Code: StatusErrorNotInitialized}
+
+	// ErrInvalidArgument matches failures with StatusErrorInvalidArgument
+	ErrInvalidArgument = &Error{Code: StatusErrorInvalidArgument}
+
+	// ErrRuntime matches failures with StatusErrorRuntime
+	ErrRuntime = &Error{Code: StatusErrorRuntime}
+
+	// ErrTimeout matches failures with StatusErrorTimeout.
+	// Timeouts also match context.DeadlineExceeded.
+	ErrTimeout = &Error{Code: StatusErrorTimeout}
+)
+
+// NewError creates a new Error for the given operation and status code.
+func NewError(op string, code int, message string) *Error {
+	return &Error{
+		Op:      op,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = statusText(e.Code)
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	if e.Op == "" {
+		return fmt.Sprintf("easyq bridge error (code %d): %s", e.Code, message)
+	}
+	return fmt.Sprintf("easyq bridge error (code %d): %s: %s", e.Code, e.Op, message)
+}
+
+// Unwrap returns the underlying cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether e matches target. An *Error matches a sentinel with the
+// same code, and timeouts match context.DeadlineExceeded.
+func (e *Error) Is(target error) bool {
+	if target == context.DeadlineExceeded {
+		return e.Code == StatusErrorTimeout
+	}
+
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Op == "" && t.Message == "" && t.Err == nil && t.Code == e.Code
+}
+
+// statusText returns a description of a status code
+func statusText(code int) string {
+	switch code {
+	case StatusSuccess:
+		return "success"
+	case StatusErrorGeneral:
+		return "general error"
+	case StatusErrorNotInitialized:
+		return "not initialized"
+	case StatusErrorInvalidArgument:
+		return "invalid argument"
+	case StatusErrorRuntime:
+		return "runtime error"
+	case StatusErrorTimeout:
+		return "timeout"
+	default:
+		return "unknown error"
+	}
+}
+
+// wrapError converts an error returned by a backend into an *Error for op.
+// Errors that are already an *Error keep their code; context errors map to
+// StatusErrorTimeout or StatusErrorGeneral, and anything else to StatusErrorGeneral.
+func wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var bridgeErr *Error
+	if errors.As(err, &bridgeErr) {
+		if bridgeErr.Op == "" {
+			wrapped := *bridgeErr
+			wrapped.Op = op
+			return &wrapped
+		}
+		return bridgeErr
+	}
+
+	code := StatusErrorGeneral
+	if errors.Is(err, context.DeadlineExceeded) {
+		code = StatusErrorTimeout
+	}
+	return &Error{Op: op, Code: code, Err: err}
+}
@@ -0,0 +1,69 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorIsSentinel(t *testing.T) {
+	sentinels := []*Error{ErrGeneral, ErrNotInitialized, ErrInvalidArgument, ErrRuntime, ErrTimeout}
+
+	for _, sentinel := range sentinels {
+		err := fmt.Errorf("wrapped: %w", NewError("Search", sentinel.Code, "failed"))
+		for _, target := range sentinels {
+			if got, want := errors.Is(err, target), target == sentinel; got != want {
+				t.Errorf("errors.Is(code %d, code %d) = %v, want %v", sentinel.Code, target.Code, got, want)
+			}
+		}
+	}
+
+	// A non-sentinel *Error target must not match on code alone
+	if errors.Is(NewError("Search", StatusErrorRuntime, ""), NewError("Count", StatusErrorRuntime, "")) {
+		t.Error("errors.Is matched a non-sentinel *Error target")
+	}
+}
+
+func TestErrorTimeoutDeadlineExceeded(t *testing.T) {
+	if !errors.Is(NewError("Search", StatusErrorTimeout, ""), context.DeadlineExceeded) {
+		t.Error("timeout error does not match context.DeadlineExceeded")
+	}
+	if errors.Is(NewError("Search", StatusErrorRuntime, ""), context.DeadlineExceeded) {
+		t.Error("runtime error matches context.DeadlineExceeded")
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"deadline", context.DeadlineExceeded, StatusErrorTimeout},
+		{"canceled", context.Canceled, StatusErrorGeneral},
+		{"other", errors.New("boom"), StatusErrorGeneral},
+		{"bridge error", &Error{Code: StatusErrorInvalidArgument}, StatusErrorInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapError("Search", tt.err)
+
+			var bridgeErr *Error
+			if !errors.As(err, &bridgeErr) {
+				t.Fatalf("wrapError returned %T, want *Error", err)
+			}
+			if bridgeErr.Op != "Search" || bridgeErr.Code != tt.wantCode {
+				t.Errorf("wrapError = {Op: %q, Code: %d}, want {Op: \"Search\", Code: %d}", bridgeErr.Op, bridgeErr.Code, tt.wantCode)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("wrapped error does not match its cause %v", tt.err)
+			}
+		})
+	}
+
+	if wrapError("Search", nil) != nil {
+		t.Error("wrapError(nil) != nil")
+	}
+}
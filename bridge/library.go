@@ -0,0 +1,59 @@
+package bridge
+
+import (
+	"os"
+	"runtime"
+	"sync"
+)
+
+// LibraryPathEnv is the environment variable that overrides the location of
+// the native EasyQBridge library.
+const LibraryPathEnv = "EASYQ_BRIDGE_PATH"
+
+// ABI versions of the native library supported by this package. The native
+// library reports its version through EasyQ_GetVersion; it is accepted if its
+// major version matches and its minor version is at least ABIVersionMinMinor.
+const (
+	ABIVersionMajor    = 1
+	ABIVersionMinMinor = 0
+)
+
+var (
+	libraryPathMutex sync.RWMutex
+	libraryPath      string
+)
+
+// SetLibraryPath sets the path of the native EasyQBridge library loaded by the
+// native backend. An empty path restores the default lookup.
+// It only takes effect before the library has been loaded.
+func SetLibraryPath(path string) {
+	libraryPathMutex.Lock()
+	defer libraryPathMutex.Unlock()
+
+	libraryPath = path
+}
+
+// LibraryPath returns the path the native backend loads the EasyQBridge library from.
+// In order of precedence this is the path set with SetLibraryPath, the value of
+// EASYQ_BRIDGE_PATH, or the platform's library name resolved by the dynamic loader.
+func LibraryPath() string {
+	libraryPathMutex.RLock()
+	path := libraryPath
+	libraryPathMutex.RUnlock()
+
+	if path != "" {
+		return path
+	}
+	if path := os.Getenv(LibraryPathEnv); path != "" {
+		return path
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		return "EasyQBridge.dll"
+	case "darwin":
+		return "libEasyQBridge.dylib"
+	default:
+		return "libEasyQBridge.so"
+	}
+}
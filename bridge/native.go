@@ -0,0 +1,534 @@
+//go:build easyq_native
+
+package bridge
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <stdlib.h>
+#include <stdint.h>
+#include "bridge.h"
+
+#ifdef _WIN32
+#include <windows.h>
+
+static void* easyq_dlopen(const char* path) { return (void*)LoadLibraryA(path); }
+static void* easyq_dlsym(void* handle, const char* name) { return (void*)GetProcAddress((HMODULE)handle, name); }
+static const char* easyq_dlerror(void) { return NULL; }
+static void easyq_dlclose(void* handle) { FreeLibrary((HMODULE)handle); }
+#else
+#include <dlfcn.h>
+
+static void* easyq_dlopen(const char* path) { return dlopen(path, RTLD_NOW | RTLD_LOCAL); }
+static void* easyq_dlsym(void* handle, const char* name) { return dlsym(handle, name); }
+static const char* easyq_dlerror(void) { return dlerror(); }
+static void easyq_dlclose(void* handle) { dlclose(handle); }
+#endif
+
+// Trampolines calling the resolved library functions
+static int easyq_call_get_version(void* f, int* major, int* minor) { return ((int (*)(int*, int*))f)(major, minor); }
+static int easyq_call_initialize(void* f) { return ((int (*)(void))f)(); }
+static void easyq_call_shutdown(void* f) { ((void (*)(void))f)(); }
+static int easyq_call_configure_connection(void* f, const char* config) { return ((int (*)(const char*))f)(config); }
+static void easyq_call_free_string(void* f, char* str) { ((void (*)(char*))f)(str); }
+static int easyq_call_search(void* f, const char* items, const char* predicate, const char* options, char** result) {
+	return ((int (*)(const char*, const char*, const char*, char**))f)(items, predicate, options, result);
+}
+static int easyq_call_generate_random_int(void* f, int min, int max, int* result) { return ((int (*)(int, int, int*))f)(min, max, result); }
+static int easyq_call_generate_random_bytes(void* f, int length, unsigned char* buffer) { return ((int (*)(int, unsigned char*))f)(length, buffer); }
+static int easyq_call_generate_key(void* f, const char* options, char** result) { return ((int (*)(const char*, char**))f)(options, result); }
+static int easyq_call_get_last_error(void* f, char** message) { return ((int (*)(char**))f)(message); }
+static int easyq_call_count(void* f, const char* items, const char* predicate, const char* options, char** result) {
+	return ((int (*)(const char*, const char*, const char*, char**))f)(items, predicate, options, result);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// nativeSymbols holds the resolved entry points of the native library
+type nativeSymbols struct {
+	getVersion          unsafe.Pointer
+	initialize          unsafe.Pointer
+	shutdown            unsafe.Pointer
+	configureConnection unsafe.Pointer
+	freeString          unsafe.Pointer
+	search              unsafe.Pointer
+	generateRandomInt   unsafe.Pointer
+	generateRandomBytes unsafe.Pointer
+	generateKey         unsafe.Pointer
+
+	// getLastError is only available from ABI version 1.1
+	getLastError unsafe.Pointer
+
+	// count is only available from ABI version 1.2
+	count unsafe.Pointer
+}
+
+// NativeBackend is the Backend implemented by the native EasyQBridge shared
+// library built from the C# bridge.
+//
+// The library is loaded at runtime from LibraryPath when the backend is first
+// initialized, so no native library is needed to build the package.
+// The native library holds process-wide state, so all NativeBackend values
+// share the same underlying runtime, and its connection configuration is
+// shared by every session using it. Initialize and Shutdown are reference
+// counted, so the runtime stays up until the last user shuts it down.
+//
+// Methods check their context before calling into the library, but a native
+// call that has started runs to completion even if the context is canceled.
+type NativeBackend struct {
+	mu      sync.Mutex
+	symbols *nativeSymbols
+	major   int
+	minor   int
+	refs    int
+}
+
+// NewNativeBackend returns a Backend backed by the native EasyQBridge library.
+func NewNativeBackend() *NativeBackend {
+	return &NativeBackend{}
+}
+
+// ABIVersion returns the ABI version reported by the loaded native library.
+// It returns 0, 0 if the library has not been loaded yet.
+func (b *NativeBackend) ABIVersion() (major, minor int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.major, b.minor
+}
+
+// load opens the native library, resolves every function declared in
+// bridge.h and checks that the library speaks a supported ABI version.
+// The library is closed again if any check fails. The caller must hold b.mu.
+func (b *NativeBackend) load() (err error) {
+	if b.symbols != nil {
+		return nil
+	}
+
+	path := LibraryPath()
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	handle := C.easyq_dlopen(cPath)
+	if handle == nil {
+		if reason := C.easyq_dlerror(); reason != nil {
+			return NewError("Initialize", StatusErrorGeneral, fmt.Sprintf("failed to load EasyQBridge library %q: %s", path, C.GoString(reason)))
+		}
+		return NewError("Initialize", StatusErrorGeneral, fmt.Sprintf("failed to load EasyQBridge library %q", path))
+	}
+	defer func() {
+		if err != nil {
+			C.easyq_dlclose(handle)
+		}
+	}()
+
+	symbols := &nativeSymbols{}
+	lookup := func(name string, target *unsafe.Pointer) error {
+		cName := C.CString(name)
+		address := C.easyq_dlsym(handle, cName)
+		C.free(unsafe.Pointer(cName))
+
+		if address == nil {
+			return NewError("Initialize", StatusErrorGeneral, fmt.Sprintf("EasyQBridge library %q is missing symbol %s", path, name))
+		}
+		*target = address
+		return nil
+	}
+
+	for _, symbol := range []struct {
+		name   string
+		target *unsafe.Pointer
+	}{
+		{"EasyQ_GetVersion", &symbols.getVersion},
+		{"EasyQ_Initialize", &symbols.initialize},
+		{"EasyQ_Shutdown", &symbols.shutdown},
+		{"EasyQ_ConfigureConnection", &symbols.configureConnection},
+		{"EasyQ_FreeString", &symbols.freeString},
+		{"EasyQ_Search", &symbols.search},
+		{"EasyQ_GenerateRandomInt", &symbols.generateRandomInt},
+		{"EasyQ_GenerateRandomBytes", &symbols.generateRandomBytes},
+		{"EasyQ_GenerateKey", &symbols.generateKey},
+	} {
+		if err := lookup(symbol.name, symbol.target); err != nil {
+			return err
+		}
+	}
+
+	var major, minor C.int
+	if status := C.easyq_call_get_version(symbols.getVersion, &major, &minor); status != StatusSuccess {
+		return NewError("Initialize", int(status), "failed to query EasyQBridge ABI version")
+	}
+	if int(major) != ABIVersionMajor || int(minor) < ABIVersionMinMinor {
+		return NewError("Initialize", StatusErrorGeneral, fmt.Sprintf(
+			"EasyQBridge library %q has unsupported ABI version %d.%d (supported: %d.%d and later %d.x)",
+			path, major, minor, ABIVersionMajor, ABIVersionMinMinor, ABIVersionMajor))
+	}
+
+	// Libraries implementing ABI 1.1 or later report native error messages
+	if minor >= 1 {
+		if err := lookup("EasyQ_GetLastError", &symbols.getLastError); err != nil {
+			return err
+		}
+	}
+
+	// Libraries implementing ABI 1.2 or later support quantum counting
+	if minor >= 2 {
+		if err := lookup("EasyQ_Count", &symbols.count); err != nil {
+			return err
+		}
+	}
+
+	b.symbols = symbols
+	b.major, b.minor = int(major), int(minor)
+	return nil
+}
+
+// Initialize loads the native library if necessary and initializes the quantum runtime.
+func (b *NativeBackend) Initialize() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.load(); err != nil {
+		return err
+	}
+
+	if b.refs == 0 {
+		if err := b.call(context.Background(), "Initialize", b.symbols, func() C.int {
+			return C.easyq_call_initialize(b.symbols.initialize)
+		}); err != nil {
+			return err
+		}
+	}
+	b.refs++
+	return nil
+}
+
+// Shutdown cleans up resources used by the native quantum runtime once every
+// user has shut it down. The library itself stays loaded so the backend can be
+// initialized again.
+func (b *NativeBackend) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.refs == 0 {
+		return
+	}
+	b.refs--
+	if b.refs == 0 {
+		C.easyq_call_shutdown(b.symbols.shutdown)
+	}
+}
+
+// loaded returns the entry points of the native library for op, or an error
+// with StatusErrorNotInitialized if the runtime is not initialized. The
+// library stays loaded once it has been, so the entry points remain valid
+// after b.mu is released.
+func (b *NativeBackend) loaded(op string) (*nativeSymbols, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.symbols == nil || b.refs == 0 {
+		return nil, NewError(op, StatusErrorNotInitialized, "EasyQBridge library not initialized")
+	}
+	return b.symbols, nil
+}
+
+// ConfigureConnection configures the connection to a quantum computing resource.
+func (b *NativeBackend) ConfigureConnection(ctx context.Context, config interface{}) error {
+	symbols, err := b.loaded("ConfigureConnection")
+	if err != nil {
+		return err
+	}
+
+	// Convert the config to JSON
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return encodingError("ConfigureConnection", StatusErrorInvalidArgument, "failed to marshal connection config", err)
+	}
+
+	// Convert JSON to C string
+	cConfigJSON := C.CString(string(configJSON))
+	defer C.free(unsafe.Pointer(cConfigJSON))
+
+	// Call the DLL function
+	if err := b.call(ctx, "ConfigureConnection", symbols, func() C.int {
+		return C.easyq_call_configure_connection(symbols.configureConnection, cConfigJSON)
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Search performs a quantum search using Grover's algorithm.
+func (b *NativeBackend) Search(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, error) {
+	results, _, err := b.SearchWithReport(ctx, items, predicate, options)
+	return results, err
+}
+
+// SearchWithReport performs a quantum search like Search, and also returns the
+// report of the run if the library provides one. It implements SearchReporter.
+func (b *NativeBackend) SearchWithReport(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, map[string]interface{}, error) {
+	symbols, err := b.loaded("Search")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Convert parameters to JSON
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, nil, encodingError("Search", StatusErrorInvalidArgument, "failed to marshal items", err)
+	}
+
+	predicateJSON, err := json.Marshal(predicate)
+	if err != nil {
+		return nil, nil, encodingError("Search", StatusErrorInvalidArgument, "failed to marshal predicate", err)
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, nil, encodingError("Search", StatusErrorInvalidArgument, "failed to marshal options", err)
+	}
+
+	// Convert JSON to C strings
+	cItemsJSON := C.CString(string(itemsJSON))
+	defer C.free(unsafe.Pointer(cItemsJSON))
+
+	cPredicateJSON := C.CString(string(predicateJSON))
+	defer C.free(unsafe.Pointer(cPredicateJSON))
+
+	cOptionsJSON := C.CString(string(optionsJSON))
+	defer C.free(unsafe.Pointer(cOptionsJSON))
+
+	// Prepare for result
+	var cResultJSON *C.char
+
+	// Call the DLL function
+	if err := b.call(ctx, "Search", symbols, func() C.int {
+		return C.easyq_call_search(symbols.search, cItemsJSON, cPredicateJSON, cOptionsJSON, &cResultJSON)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// Convert result back to Go and free the C string
+	goResultJSON := b.takeString(symbols, cResultJSON)
+
+	// Unmarshal the result, which is either an array of results or an
+	// object with the results and a report
+	var document interface{}
+	err = json.Unmarshal([]byte(goResultJSON), &document)
+	if err != nil {
+		return nil, nil, encodingError("Search", StatusErrorRuntime, "failed to unmarshal search results", err)
+	}
+
+	switch document := document.(type) {
+	case []interface{}:
+		return document, nil, nil
+	case map[string]interface{}:
+		searchResults, _ := document["Results"].([]interface{})
+		report, _ := document["Report"].(map[string]interface{})
+		return searchResults, report, nil
+	default:
+		return nil, nil, NewError("Search", StatusErrorRuntime, fmt.Sprintf("unexpected search result document: %T", document))
+	}
+}
+
+// Count estimates the number of items matching the predicate by quantum counting.
+// It requires a library implementing ABI version 1.2 or later.
+func (b *NativeBackend) Count(ctx context.Context, items interface{}, predicate interface{}, options interface{}) (map[string]interface{}, error) {
+	symbols, err := b.loaded("Count")
+	if err != nil {
+		return nil, err
+	}
+	if symbols.count == nil {
+		major, minor := b.ABIVersion()
+		return nil, NewError("Count", StatusErrorGeneral, fmt.Sprintf(
+			"EasyQBridge library ABI version %d.%d does not support quantum counting (requires %d.2)", major, minor, ABIVersionMajor))
+	}
+
+	// Convert parameters to JSON
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, encodingError("Count", StatusErrorInvalidArgument, "failed to marshal items", err)
+	}
+
+	predicateJSON, err := json.Marshal(predicate)
+	if err != nil {
+		return nil, encodingError("Count", StatusErrorInvalidArgument, "failed to marshal predicate", err)
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, encodingError("Count", StatusErrorInvalidArgument, "failed to marshal options", err)
+	}
+
+	// Convert JSON to C strings
+	cItemsJSON := C.CString(string(itemsJSON))
+	defer C.free(unsafe.Pointer(cItemsJSON))
+
+	cPredicateJSON := C.CString(string(predicateJSON))
+	defer C.free(unsafe.Pointer(cPredicateJSON))
+
+	cOptionsJSON := C.CString(string(optionsJSON))
+	defer C.free(unsafe.Pointer(cOptionsJSON))
+
+	// Prepare for result
+	var cResultJSON *C.char
+
+	// Call the DLL function
+	if err := b.call(ctx, "Count", symbols, func() C.int {
+		return C.easyq_call_count(symbols.count, cItemsJSON, cPredicateJSON, cOptionsJSON, &cResultJSON)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Convert result back to Go and free the C string
+	goResultJSON := b.takeString(symbols, cResultJSON)
+
+	// Unmarshal the result
+	var countResult map[string]interface{}
+	err = json.Unmarshal([]byte(goResultJSON), &countResult)
+	if err != nil {
+		return nil, encodingError("Count", StatusErrorRuntime, "failed to unmarshal count result", err)
+	}
+
+	return countResult, nil
+}
+
+// GenerateRandomInt generates a random integer using quantum measurement.
+func (b *NativeBackend) GenerateRandomInt(ctx context.Context, min, max int) (int, error) {
+	symbols, err := b.loaded("GenerateRandomInt")
+	if err != nil {
+		return 0, err
+	}
+
+	// Prepare for result
+	var result C.int
+
+	// Call the DLL function
+	if err := b.call(ctx, "GenerateRandomInt", symbols, func() C.int {
+		return C.easyq_call_generate_random_int(symbols.generateRandomInt, C.int(min), C.int(max), &result)
+	}); err != nil {
+		return 0, err
+	}
+
+	return int(result), nil
+}
+
+// GenerateRandomBytes generates random bytes using quantum measurement.
+func (b *NativeBackend) GenerateRandomBytes(ctx context.Context, length int) ([]byte, error) {
+	symbols, err := b.loaded("GenerateRandomBytes")
+	if err != nil {
+		return nil, err
+	}
+
+	// Allocate a buffer for the result
+	buffer := make([]byte, length)
+
+	// Call the DLL function
+	if err := b.call(ctx, "GenerateRandomBytes", symbols, func() C.int {
+		return C.easyq_call_generate_random_bytes(symbols.generateRandomBytes, C.int(length), (*C.uchar)(unsafe.Pointer(&buffer[0])))
+	}); err != nil {
+		return nil, err
+	}
+
+	return buffer, nil
+}
+
+// GenerateKey generates a key using quantum key distribution.
+func (b *NativeBackend) GenerateKey(ctx context.Context, options interface{}) (map[string]interface{}, error) {
+	symbols, err := b.loaded("GenerateKey")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert options to JSON
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, encodingError("GenerateKey", StatusErrorInvalidArgument, "failed to marshal options", err)
+	}
+
+	// Convert JSON to C string
+	cOptionsJSON := C.CString(string(optionsJSON))
+	defer C.free(unsafe.Pointer(cOptionsJSON))
+
+	// Prepare for result
+	var cResultJSON *C.char
+
+	// Call the DLL function
+	if err := b.call(ctx, "GenerateKey", symbols, func() C.int {
+		return C.easyq_call_generate_key(symbols.generateKey, cOptionsJSON, &cResultJSON)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Convert result back to Go and free the C string
+	goResultJSON := b.takeString(symbols, cResultJSON)
+
+	// Unmarshal the result
+	var keyResult map[string]interface{}
+	err = json.Unmarshal([]byte(goResultJSON), &keyResult)
+	if err != nil {
+		return nil, encodingError("GenerateKey", StatusErrorRuntime, "failed to unmarshal key distribution result", err)
+	}
+
+	return keyResult, nil
+}
+
+// call invokes a native function and converts a failure status into an *Error
+// for op, including the library's error message when it reports one.
+// If ctx is already done, fn is not called. A native call cannot be
+// interrupted once it has started, so ctx is not checked again until it returns.
+// The OS thread is locked for the duration, as the library keeps the last
+// error message per thread.
+func (b *NativeBackend) call(ctx context.Context, op string, symbols *nativeSymbols, fn func() C.int) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError(op, err)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	status := fn()
+	if status == StatusSuccess {
+		return nil
+	}
+	return NewError(op, int(status), b.lastError(symbols))
+}
+
+// lastError returns the message of the last failed call on the current OS thread,
+// or an empty string if the library does not provide one.
+func (b *NativeBackend) lastError(symbols *nativeSymbols) string {
+	if symbols.getLastError == nil {
+		return ""
+	}
+
+	var message *C.char
+	if C.easyq_call_get_last_error(symbols.getLastError, &message) != StatusSuccess {
+		return ""
+	}
+	return b.takeString(symbols, message)
+}
+
+// takeString copies a string returned by the library and releases it with EasyQ_FreeString
+func (b *NativeBackend) takeString(symbols *nativeSymbols, str *C.char) string {
+	if str == nil {
+		return ""
+	}
+	goStr := C.GoString(str)
+	C.easyq_call_free_string(symbols.freeString, str)
+	return goStr
+}
+
+// encodingError reports a failure to convert values to or from JSON for op
+func encodingError(op string, code int, message string, err error) error {
+	return &Error{Op: op, Code: code, Message: message, Err: err}
+}
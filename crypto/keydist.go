@@ -1,10 +1,10 @@
 package crypto
 
 import (
+	"context"
 	"math"
 
 	easyq "github.com/Henrikarba/easyq-go"
-	"github.com/Henrikarba/easyq-go/bridge"
 )
 
 // DefaultKeyDistributionOptions returns a new set of default options for key distribution
@@ -30,8 +30,16 @@ func DefaultKeyDistributionOptions() easyq.KeyDistributionOptions {
 //	// Generate a 256-bit quantum-secure key with default options
 //	result, err := crypto.GenerateKey(nil)
 func GenerateKey(options *easyq.KeyDistributionOptions) (*easyq.KeyDistributionResult, error) {
-	// Ensure we're initialized
-	if err := easyq.EnsureInitialized(); err != nil {
+	return GenerateKeyContext(context.Background(), options)
+}
+
+// GenerateKeyContext is like GenerateKey but honours the deadline and cancellation of ctx.
+// If ctx is done before the key is generated, ctx.Err() is returned, although
+// a native backend call that has already started keeps running until it returns.
+func GenerateKeyContext(ctx context.Context, options *easyq.KeyDistributionOptions) (*easyq.KeyDistributionResult, error) {
+	// Resolve the session to run on, initializing if necessary
+	client, err := sessionClient(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -51,7 +59,7 @@ func GenerateKey(options *easyq.KeyDistributionOptions) (*easyq.KeyDistributionR
 	}
 
 	// Generate key using the bridge
-	rawResult, err := bridge.GenerateKey(opts)
+	rawResult, err := client.GenerateKey(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +132,12 @@ func GenerateKey(options *easyq.KeyDistributionOptions) (*easyq.KeyDistributionR
 // - errorRate: the observed error rate in measurements
 // - error: any error that occurred during verification
 func VerifyChannelSecurity(options *easyq.KeyDistributionOptions) (bool, float64, float64, error) {
+	return VerifyChannelSecurityContext(context.Background(), options)
+}
+
+// VerifyChannelSecurityContext is like VerifyChannelSecurity but honours the
+// deadline and cancellation of ctx.
+func VerifyChannelSecurityContext(ctx context.Context, options *easyq.KeyDistributionOptions) (bool, float64, float64, error) {
 	// Use a small key length for verification only
 	opts := DefaultKeyDistributionOptions()
 	if options != nil {
@@ -135,7 +149,7 @@ func VerifyChannelSecurity(options *easyq.KeyDistributionOptions) (bool, float64
 	opts.MaxAttempts = 2 // Fewer attempts since we're just testing
 
 	// Generate a short key to test the channel
-	result, err := GenerateKey(&opts)
+	result, err := GenerateKeyContext(ctx, &opts)
 	if err != nil {
 		// If it's not specifically a key generation failure, return the error
 		if err != easyq.ErrKeyGenerationFailed {
@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+func TestGenerateKey(t *testing.T) {
+	result, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Key) != 32 {
+		t.Errorf("key has %d bytes, want 32", len(result.Key))
+	}
+	// Success requires the CHSH value to reach SecurityThreshold, and only
+	// sampling noise takes it above 2√2
+	if result.SecurityParameter < 2.2 || result.SecurityParameter > 2*math.Sqrt2+0.5 {
+		t.Errorf("CHSH value = %.3f, want between 2.2 and about %.3f", result.SecurityParameter, 2*math.Sqrt2)
+	}
+	if result.ErrorRate != 0 {
+		t.Errorf("error rate = %.3f, want 0", result.ErrorRate)
+	}
+	if len(result.AuthenticationTag) == 0 {
+		t.Error("missing authentication tag with Standard authentication")
+	}
+}
+
+func TestGenerateKeyInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*easyq.KeyDistributionOptions)
+		wantErr error
+	}{
+		{"zero length", func(o *easyq.KeyDistributionOptions) { o.KeyLength = 0 }, easyq.ErrInvalidLength},
+		{"security level too low", func(o *easyq.KeyDistributionOptions) { o.SecurityLevel = 0 }, easyq.ErrInvalidSecurityLevel},
+		{"security level too high", func(o *easyq.KeyDistributionOptions) { o.SecurityLevel = 6 }, easyq.ErrInvalidSecurityLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultKeyDistributionOptions()
+			tt.modify(&opts)
+			if _, err := GenerateKey(&opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GenerateKey error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyChannelSecurity(t *testing.T) {
+	secure, chsh, errorRate, err := VerifyChannelSecurity(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !secure {
+		t.Errorf("channel reported insecure: CHSH %.3f, error rate %.3f", chsh, errorRate)
+	}
+	if chsh <= 2 {
+		t.Errorf("CHSH value = %.3f, want a violation of the classical bound 2", chsh)
+	}
+}
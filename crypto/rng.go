@@ -3,6 +3,7 @@
 package crypto
 
 import (
+	"context"
 	"errors"
 
 	easyq "github.com/Henrikarba/easyq-go"
@@ -20,16 +21,23 @@ import (
 //	// Generate a random number between 1 and 100
 //	num, err := crypto.RandomInt(1, 100)
 func RandomInt(min, max int) (int, error) {
+	return RandomIntContext(context.Background(), min, max)
+}
+
+// RandomIntContext is like RandomInt but honours the deadline and cancellation of ctx.
+// Cancellation does not interrupt a native backend call that has already started.
+func RandomIntContext(ctx context.Context, min, max int) (int, error) {
 	if min >= max {
 		return 0, easyq.ErrInvalidRange
 	}
 
-	// Ensure we're initialized
-	if err := easyq.EnsureInitialized(); err != nil {
+	// Resolve the session to run on, initializing if necessary
+	client, err := sessionClient(ctx)
+	if err != nil {
 		return 0, err
 	}
 
-	return bridge.GenerateRandomInt(min, max)
+	return client.GenerateRandomInt(ctx, min, max)
 }
 
 // RandomBytes generates a sequence of random bytes using quantum measurement.
@@ -40,16 +48,23 @@ func RandomInt(min, max int) (int, error) {
 //	// Generate 32 random bytes (256 bits)
 //	bytes, err := crypto.RandomBytes(32)
 func RandomBytes(length int) ([]byte, error) {
+	return RandomBytesContext(context.Background(), length)
+}
+
+// RandomBytesContext is like RandomBytes but honours the deadline and cancellation of ctx.
+// Cancellation does not interrupt a native backend call that has already started.
+func RandomBytesContext(ctx context.Context, length int) ([]byte, error) {
 	if length <= 0 {
 		return nil, easyq.ErrInvalidLength
 	}
 
-	// Ensure we're initialized
-	if err := easyq.EnsureInitialized(); err != nil {
+	// Resolve the session to run on, initializing if necessary
+	client, err := sessionClient(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	return bridge.GenerateRandomBytes(length)
+	return client.GenerateRandomBytes(ctx, length)
 }
 
 // RandomPermutation generates a random permutation of integers from 0 to length-1
@@ -60,12 +75,18 @@ func RandomBytes(length int) ([]byte, error) {
 //	// Generate a random permutation of 0-9
 //	perm, err := crypto.RandomPermutation(10)
 func RandomPermutation(length int) ([]int, error) {
+	return RandomPermutationContext(context.Background(), length)
+}
+
+// RandomPermutationContext is like RandomPermutation but honours the deadline
+// and cancellation of ctx.
+func RandomPermutationContext(ctx context.Context, length int) ([]int, error) {
 	if length <= 0 {
 		return nil, easyq.ErrInvalidLength
 	}
 
-	// Ensure we're initialized
-	if err := easyq.EnsureInitialized(); err != nil {
+	// Resolve the session to run on, initializing if necessary
+	if _, err := easyq.SessionFromContext(ctx); err != nil {
 		return nil, err
 	}
 
@@ -77,7 +98,7 @@ func RandomPermutation(length int) ([]int, error) {
 
 	for i := length - 1; i > 0; i-- {
 		// Use quantum randomness to select an index
-		j, err := RandomInt(0, i)
+		j, err := RandomIntContext(ctx, 0, i)
 		if err != nil {
 			return nil, err
 		}
@@ -92,17 +113,24 @@ func RandomPermutation(length int) ([]int, error) {
 // FillRandomBuffer fills the provided buffer with random bytes
 // using quantum random number generation.
 func FillRandomBuffer(buffer []byte) error {
+	return FillRandomBufferContext(context.Background(), buffer)
+}
+
+// FillRandomBufferContext is like FillRandomBuffer but honours the deadline
+// and cancellation of ctx.
+func FillRandomBufferContext(ctx context.Context, buffer []byte) error {
 	if len(buffer) == 0 {
 		return errors.New("buffer cannot be empty")
 	}
 
-	// Ensure we're initialized
-	if err := easyq.EnsureInitialized(); err != nil {
+	// Resolve the session to run on, initializing if necessary
+	client, err := sessionClient(ctx)
+	if err != nil {
 		return err
 	}
 
 	// Generate random bytes
-	randomBytes, err := bridge.GenerateRandomBytes(len(buffer))
+	randomBytes, err := client.GenerateRandomBytes(ctx, len(buffer))
 	if err != nil {
 		return err
 	}
@@ -111,3 +139,13 @@ func FillRandomBuffer(buffer []byte) error {
 	copy(buffer, randomBytes)
 	return nil
 }
+
+// sessionClient returns the bridge client of the session attached to ctx,
+// or of the default session, initializing the runtime if necessary
+func sessionClient(ctx context.Context) (*bridge.Client, error) {
+	session, err := easyq.SessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return session.Client()
+}
@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+func TestRandomInt(t *testing.T) {
+	tests := []struct {
+		min, max int
+	}{
+		{0, 1},
+		{1, 6},
+		{-5, 5},
+		{100, 1000},
+	}
+
+	for _, tt := range tests {
+		for range 100 {
+			value, err := RandomInt(tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("RandomInt(%d, %d): %v", tt.min, tt.max, err)
+			}
+			if value < tt.min || value > tt.max {
+				t.Fatalf("RandomInt(%d, %d) = %d, out of range", tt.min, tt.max, value)
+			}
+		}
+	}
+}
+
+func TestRandomIntInvalidRange(t *testing.T) {
+	for _, bounds := range [][2]int{{1, 1}, {5, 2}} {
+		if _, err := RandomInt(bounds[0], bounds[1]); !errors.Is(err, easyq.ErrInvalidRange) {
+			t.Errorf("RandomInt(%d, %d) error = %v, want ErrInvalidRange", bounds[0], bounds[1], err)
+		}
+	}
+}
+
+func TestRandomBytes(t *testing.T) {
+	for _, length := range []int{1, 32, 100} {
+		buffer, err := RandomBytes(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(buffer) != length {
+			t.Errorf("RandomBytes(%d) returned %d bytes", length, len(buffer))
+		}
+	}
+
+	if _, err := RandomBytes(0); !errors.Is(err, easyq.ErrInvalidLength) {
+		t.Errorf("RandomBytes(0) error = %v, want ErrInvalidLength", err)
+	}
+}
+
+func TestRandomPermutation(t *testing.T) {
+	permutation, err := RandomPermutation(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorted := slices.Sorted(slices.Values(permutation))
+	for i, value := range sorted {
+		if value != i {
+			t.Fatalf("RandomPermutation(50) = %v, not a permutation of [0, 50)", permutation)
+		}
+	}
+}
@@ -1,18 +1,26 @@
 // Package easyq provides a developer-friendly API for quantum computing operations
 // without requiring specialized knowledge of quantum mechanics or computing principles.
+//
+// Functions with a Context suffix stop waiting for the backend when their
+// context is done. On the native backend, a call that has already reached the
+// library cannot be interrupted: it runs to completion in the background and
+// its result is discarded.
 package easyq
 
 import (
+	"context"
 	"sync"
 
 	"github.com/Henrikarba/easyq-go/bridge"
 )
 
 var (
-	// Global initialization state
-	initOnce      sync.Once
-	initErr       error
-	isInitialized bool
+	// Global initialization state. The runtime is initialized exactly when
+	// defaultSession is non-nil; both are guarded by lifecycleMutex.
+	lifecycleMutex sync.RWMutex
+
+	// defaultSession serves package-level operations without a session in their context
+	defaultSession *Session
 )
 
 // Initialize sets up the EasyQ runtime and prepares it for use.
@@ -20,63 +28,132 @@ var (
 // If not called explicitly, it will be called automatically on first use of
 // any functionality in the search or crypto packages.
 //
-// Returns an error if initialization fails.
+// Calling Initialize on an initialized runtime does nothing. After Shutdown,
+// Initialize sets the runtime up again from scratch.
+//
+// Returns an error if initialization fails; a later call tries again.
 func Initialize() error {
-	initOnce.Do(func() {
-		initErr = bridge.Initialize()
-		if initErr == nil {
-			isInitialized = true
-		}
+	return InitializeWithOptions(InitOptions{})
+}
+
+// InitializeWithOptions sets up the EasyQ runtime like Initialize, using the given options.
+// Options only take effect on the call that actually initializes the runtime.
+//
+// Example:
+//
+//	err := easyq.InitializeWithOptions(easyq.InitOptions{
+//		BridgePath: "/opt/easyq/lib/libEasyQBridge.so",
+//	})
+func InitializeWithOptions(options InitOptions) error {
+	_, err := initialize(options)
+	return err
+}
+
+// initialize returns the default session, creating it if the runtime is not initialized
+func initialize(options InitOptions) (*Session, error) {
+	// Fast path for an initialized runtime
+	lifecycleMutex.RLock()
+	session := defaultSession
+	lifecycleMutex.RUnlock()
+	if session != nil {
+		return session, nil
+	}
+
+	lifecycleMutex.Lock()
+	defer lifecycleMutex.Unlock()
+
+	if defaultSession != nil {
+		return defaultSession, nil
+	}
+
+	if options.BridgePath != "" {
+		bridge.SetLibraryPath(options.BridgePath)
+	}
+
+	// The default session starts on the simulator until a connection is configured
+	session, err := NewSession(QuantumConnectionConfig{
+		BackendType: Simulator,
 	})
-	return initErr
+	if err != nil {
+		return nil, err
+	}
+
+	defaultSession = session
+	return session, nil
 }
 
 // Shutdown cleans up resources used by the EasyQ runtime.
 // It should be called when your application is shutting down.
+//
+// Shutdown waits for operations in flight on the default session to finish.
+// The connection configured with SetQuantumConnection is discarded, so the
+// next initialization starts on the default simulator again.
 func Shutdown() {
-	if isInitialized {
-		bridge.Shutdown()
-		isInitialized = false
+	lifecycleMutex.Lock()
+	defer lifecycleMutex.Unlock()
+
+	if defaultSession != nil {
+		defaultSession.Close()
+		defaultSession = nil
 	}
 }
 
 // IsInitialized returns whether the EasyQ runtime has been initialized
 func IsInitialized() bool {
-	return isInitialized
+	lifecycleMutex.RLock()
+	defer lifecycleMutex.RUnlock()
+
+	return defaultSession != nil
 }
 
 // EnsureInitialized ensures the package is initialized
 // This is used internally by the subpackages
 func EnsureInitialized() error {
-	if !isInitialized {
-		return Initialize()
-	}
-	return nil
+	return Initialize()
+}
+
+// DefaultSession returns the session used by package-level operations,
+// initializing the runtime if necessary.
+func DefaultSession() (*Session, error) {
+	return initialize(InitOptions{})
 }
 
 // UseDefaultSimulator sets up a simulation backend (no real quantum hardware)
 // This is the default if no connection is configured
 func UseDefaultSimulator() error {
-	return SetQuantumConnection(QuantumConnectionConfig{
+	return UseDefaultSimulatorContext(context.Background())
+}
+
+// UseDefaultSimulatorContext is like UseDefaultSimulator but honours the
+// deadline and cancellation of ctx.
+func UseDefaultSimulatorContext(ctx context.Context) error {
+	return SetQuantumConnectionContext(ctx, QuantumConnectionConfig{
 		BackendType: Simulator,
 	})
 }
 
-// SetQuantumConnection configures the connection to a quantum computing resource.
+// SetQuantumConnection configures the connection of the default session to a quantum computing resource.
 // This must be called before using any quantum operations, or the default simulator will be used.
 func SetQuantumConnection(config QuantumConnectionConfig) error {
+	return SetQuantumConnectionContext(context.Background(), config)
+}
+
+// SetQuantumConnectionContext is like SetQuantumConnection but honours the
+// deadline and cancellation of ctx while connecting.
+func SetQuantumConnectionContext(ctx context.Context, config QuantumConnectionConfig) error {
 	// Validate the configuration
 	if err := validateConnectionConfig(config); err != nil {
 		return err
 	}
 
 	// Ensure we're initialized
-	if err := EnsureInitialized(); err != nil {
+	session, err := DefaultSession()
+	if err != nil {
 		return err
 	}
 
-	// Configure the connection through the bridge
-	return bridge.ConfigureConnection(config)
+	// Reconnect the default session
+	return session.SetQuantumConnectionContext(ctx, config)
 }
 
 // GetVersion returns the current version of the EasyQ package.
@@ -0,0 +1,81 @@
+package easyq_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/bridge"
+	"github.com/Henrikarba/easyq-go/crypto"
+)
+
+func TestLifecycle(t *testing.T) {
+	t.Cleanup(easyq.Shutdown)
+
+	for cycle := range 3 {
+		if err := easyq.Initialize(); err != nil {
+			t.Fatalf("cycle %d: Initialize: %v", cycle, err)
+		}
+		if !easyq.IsInitialized() {
+			t.Fatalf("cycle %d: not initialized after Initialize", cycle)
+		}
+		if _, err := crypto.RandomInt(0, 100); err != nil {
+			t.Fatalf("cycle %d: operation: %v", cycle, err)
+		}
+
+		easyq.Shutdown()
+		if easyq.IsInitialized() {
+			t.Fatalf("cycle %d: still initialized after Shutdown", cycle)
+		}
+	}
+
+	// Shutdown discards the configured connection
+	if err := easyq.SetQuantumConnection(easyq.QuantumConnectionConfig{BackendType: easyq.Simulator, Region: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	easyq.Shutdown()
+	easyq.Shutdown()
+	session, err := easyq.DefaultSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := session.Config(); got.BackendType != easyq.Simulator || got.Region != "" {
+		t.Errorf("connection after Shutdown = %+v, want the default simulator", got)
+	}
+}
+
+func TestShutdownDuringOperations(t *testing.T) {
+	t.Cleanup(easyq.Shutdown)
+
+	for cycle := range 5 {
+		if err := easyq.Initialize(); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 64)
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 8 {
+					// Operations may find the runtime shut down, or
+					// initialize it again, but must not fail otherwise
+					_, err := crypto.RandomInt(0, 100)
+					if err != nil && !errors.Is(err, easyq.ErrSessionClosed) && !errors.Is(err, bridge.ErrNotInitialized) {
+						errs <- err
+						return
+					}
+				}
+			}()
+		}
+
+		easyq.Shutdown()
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("cycle %d: operation during Shutdown: %v", cycle, err)
+		}
+	}
+}
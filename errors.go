@@ -2,7 +2,8 @@ package easyq
 
 import (
 	"errors"
-	"fmt"
+
+	"github.com/Henrikarba/easyq-go/bridge"
 )
 
 // Common errors
@@ -22,9 +23,22 @@ var (
 	// ErrUnknownBackend is returned when an unknown backend type is specified
 	ErrUnknownBackend = errors.New("easyq: unknown backend type")
 
+	// ErrSessionClosed is returned when operations are performed on a closed session
+	ErrSessionClosed = errors.New("easyq: session closed")
+
+	// ErrConnectionConflict is returned when a session would reconfigure a backend
+	// that another open session is connected through
+	ErrConnectionConflict = errors.New("easyq: backend is connected with a different configuration by another session")
+
+	// ErrBackendNotRegistered is returned when no implementation is registered for a backend type
+	ErrBackendNotRegistered = errors.New("easyq: no backend registered for backend type")
+
 	// ErrNoMatches is returned when a search operation finds no matches
 	ErrNoMatches = errors.New("easyq: no matching items found")
 
+	// ErrIncomplete is returned with the matches found when a search cannot confirm it found every match
+	ErrIncomplete = errors.New("easyq: not every matching item was found")
+
 	// ErrInvalidRange is returned when an invalid range is specified
 	ErrInvalidRange = errors.New("easyq: invalid range (min must be less than max)")
 
@@ -38,21 +52,32 @@ var (
 	ErrKeyGenerationFailed = errors.New("easyq: key generation failed")
 )
 
-// BridgeError represents an error from the native bridge
-type BridgeError struct {
-	Code    int
-	Message string
-}
+// BridgeError represents an error from the native bridge or another backend.
+// Every failed bridge call returns a *BridgeError carrying the operation name,
+// the status code and any message reported by the backend.
+type BridgeError = bridge.Error
 
-// Error implements the error interface
-func (e *BridgeError) Error() string {
-	return fmt.Sprintf("easyq bridge error (code %d): %s", e.Code, e.Message)
-}
+// Bridge errors for each status code, for use with errors.Is.
+// A *BridgeError matches the sentinel with the same Code.
+var (
+	// ErrBridgeGeneral is matched by general bridge failures
+	ErrBridgeGeneral = bridge.ErrGeneral
+
+	// ErrBridgeNotInitialized is matched when the bridge was used before initialization
+	ErrBridgeNotInitialized = bridge.ErrNotInitialized
+
+	// ErrBridgeInvalidArgument is matched when the backend rejected an argument
+	ErrBridgeInvalidArgument = bridge.ErrInvalidArgument
+
+	// ErrBridgeRuntime is matched by runtime faults in the backend
+	ErrBridgeRuntime = bridge.ErrRuntime
+
+	// ErrBridgeTimeout is matched when the backend timed out.
+	// It also matches context.DeadlineExceeded.
+	ErrBridgeTimeout = bridge.ErrTimeout
+)
 
 // NewBridgeError creates a new BridgeError
 func NewBridgeError(code int, message string) *BridgeError {
-	return &BridgeError{
-		Code:    code,
-		Message: message,
-	}
+	return bridge.NewError("", code, message)
 }
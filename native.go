@@ -0,0 +1,22 @@
+//go:build easyq_native
+
+package easyq
+
+import "github.com/Henrikarba/easyq-go/bridge"
+
+func init() {
+	// The native library serves every hardware backend type and holds
+	// process-wide state, so all of them share a single instance.
+	native := bridge.NewNativeBackend()
+	nativeFactory := func() bridge.Backend { return native }
+
+	for _, backendType := range []QuantumBackendType{
+		MicrosoftQuantumCloud,
+		IBMQuantumExperience,
+		GoogleQuantumAI,
+		LocalQuantumDevice,
+		CustomQuantumBackend,
+	} {
+		RegisterBackend(backendType, nativeFactory)
+	}
+}
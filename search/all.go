@@ -0,0 +1,142 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// SearchAll performs a quantum search that finds every item matching the
+// predicate, rather than those a fixed number of attempts happens to measure.
+// Options may be nil, in which case default options are used.
+//
+// SearchAll alternates quantum counting with amplitude amplification. Each
+// round counts the matches that have not been found yet, with
+// options.CountingPrecision qubits of precision, and searches for them with
+// the indices already found excluded from the oracle. It stops when two counts
+// in a row measure no remaining matches. With the default precision, a count
+// measures none while a match remains with a probability well under 1%.
+// Results are always verified against the predicate, and are sorted by index.
+//
+// If options.MaxAttempts rounds in a row find nothing new although the count
+// says matches remain, SearchAll returns the matches found with ErrIncomplete.
+// The backend must support quantum counting.
+//
+// Example:
+//
+//	results, err := search.SearchAll(items, predicate, nil)
+//	if errors.Is(err, easyq.ErrIncomplete) {
+//		log.Printf("found %d matches, there may be more", len(results))
+//	}
+func SearchAll(items interface{}, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	return SearchAllContext(context.Background(), items, predicate, options)
+}
+
+// SearchAllContext is like SearchAll but honours the deadline and cancellation of ctx.
+func SearchAllContext(ctx context.Context, items interface{}, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	itemsValue, sp, err := newSearchPredicate(items, predicate)
+	if err != nil {
+		return nil, err
+	}
+	size := itemsValue.Len()
+	itemType := itemsValue.Type().Elem()
+	if size == 0 {
+		return nil, easyq.ErrNoMatches
+	}
+
+	// Resolve the session to run on, initializing if necessary
+	session, err := easyq.SessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := session.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use default options if none provided
+	opts := DefaultOptions()
+	if options != nil {
+		opts = *options
+	}
+	opts.Verify = easyq.VerifyAlways
+	maxStalls := opts.MaxAttempts
+	if maxStalls <= 0 {
+		maxStalls = 5
+	}
+
+	// Only the matches not found yet are marked by the oracle
+	found := make([]bool, size)
+	remaining := searchPredicate{
+		matches: func(i int) bool {
+			return !found[i] && sp.matches(i)
+		},
+	}
+
+	compileCircuit := session.Config().BackendType != easyq.Simulator
+	var results []easyq.SearchResult
+	for stalls, zeros := 0, 0; ; {
+		mappedPredicate, err := convertPredicate(ctx, items, size, itemType, remaining, compileCircuit)
+		if err != nil {
+			return nil, err
+		}
+		counted, err := count(ctx, client, items, mappedPredicate, opts.CountingPrecision)
+		if err != nil {
+			return nil, err
+		}
+		// Only a phase of exactly zero says no matches remain: a small
+		// estimate may still stand for one match
+		if counted.Estimate == 0 {
+			zeros++
+			if zeros == 2 {
+				break
+			}
+			continue
+		}
+		zeros = 0
+
+		roundOpts := opts
+		roundOpts.KnownMatchCount = max(int(math.Round(counted.Estimate)), 1)
+		batch, _, err := search(ctx, items, size, itemType, remaining, &roundOpts)
+		if err != nil && !errors.Is(err, easyq.ErrNoMatches) {
+			return nil, err
+		}
+
+		added := 0
+		for _, result := range batch {
+			if !found[result.Index] {
+				found[result.Index] = true
+				results = append(results, result)
+				added++
+			}
+		}
+
+		if added > 0 {
+			stalls = 0
+			continue
+		}
+		stalls++
+		if stalls >= maxStalls {
+			sortByIndex(results)
+			return results, easyq.ErrIncomplete
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, easyq.ErrNoMatches
+	}
+
+	sortByIndex(results)
+	return results, nil
+}
+
+// sortByIndex sorts search results by index
+func sortByIndex(results []easyq.SearchResult) {
+	slices.SortFunc(results, func(a, b easyq.SearchResult) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/bridge"
+	"github.com/Henrikarba/easyq-go/simulator"
+)
+
+// recordingBackend is a simulator that records the indices marked by the
+// oracle of each count
+type recordingBackend struct {
+	*simulator.Backend
+
+	mu      sync.Mutex
+	counted [][]int
+}
+
+func (b *recordingBackend) Count(ctx context.Context, items interface{}, predicate interface{}, options interface{}) (map[string]interface{}, error) {
+	marked, _ := predicate.(map[string]interface{})["MarkedIndices"].([]int)
+	b.mu.Lock()
+	b.counted = append(b.counted, slices.Clone(marked))
+	b.mu.Unlock()
+
+	return b.Backend.Count(ctx, items, predicate, options)
+}
+
+// stallingBackend is a simulator whose counts always report a remaining match
+// that its searches never find
+type stallingBackend struct {
+	*simulator.Backend
+}
+
+func (b stallingBackend) Count(ctx context.Context, items interface{}, predicate interface{}, options interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{"Estimate": 1.0}, nil
+}
+
+func (b stallingBackend) Search(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func (b stallingBackend) SearchWithReport(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, map[string]interface{}, error) {
+	return []interface{}{}, nil, nil
+}
+
+// testSession returns a context running on a session with backend. The
+// backend is registered for CustomQuantumBackend, which no other test uses.
+func testSession(t *testing.T, backend bridge.Backend) context.Context {
+	t.Helper()
+
+	easyq.RegisterBackend(easyq.CustomQuantumBackend, func() bridge.Backend { return backend })
+	session, err := easyq.NewSession(easyq.QuantumConnectionConfig{
+		BackendType:      easyq.CustomQuantumBackend,
+		ProviderSettings: map[string]string{"ProviderName": "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(session.Close)
+
+	return easyq.WithSession(context.Background(), session)
+}
+
+func TestSearchAll(t *testing.T) {
+	items := make([]int, 200)
+	for i := range items {
+		items[i] = i
+	}
+	multipleOf9 := func(x int) bool { return x%9 == 0 }
+
+	var want []int
+	for _, x := range items {
+		if multipleOf9(x) {
+			want = append(want, x)
+		}
+	}
+
+	backend := &recordingBackend{Backend: simulator.NewBackend()}
+	ctx := testSession(t, backend)
+
+	opts := DefaultOptions()
+	opts.CountingPrecision = 12
+	results, err := SearchAllContext(ctx, items, multipleOf9, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indices := make([]int, len(results))
+	for i, result := range results {
+		indices[i] = result.Index
+	}
+	if !slices.Equal(indices, want) {
+		t.Fatalf("SearchAll() found indices %v, want %v", indices, want)
+	}
+
+	// Each count marks only the matches that have not been found yet, and
+	// the loop stops after two counts with nothing left
+	counted := backend.counted
+	if len(counted) < 3 {
+		t.Fatalf("ran %d counts, want at least 3", len(counted))
+	}
+	if !slices.Equal(counted[0], want) {
+		t.Errorf("first count marked %v, want every match", counted[0])
+	}
+	for i := 1; i < len(counted); i++ {
+		for _, index := range counted[i] {
+			if !slices.Contains(counted[i-1], index) {
+				t.Errorf("count %d marked %d, which the previous count did not", i, index)
+			}
+		}
+	}
+	if last := counted[len(counted)-2:]; len(last[0]) != 0 || len(last[1]) != 0 {
+		t.Errorf("last two counts marked %v, want nothing", last)
+	}
+}
+
+func TestSearchAllIncomplete(t *testing.T) {
+	ctx := testSession(t, stallingBackend{Backend: simulator.NewBackend()})
+
+	opts := DefaultOptions()
+	opts.MaxAttempts = 3
+	results, err := SearchAllContext(ctx, []int{1, 2, 3, 4}, func(x int) bool { return x == 2 }, &opts)
+	if !errors.Is(err, easyq.ErrIncomplete) {
+		t.Fatalf("error = %v, want ErrIncomplete", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d results, want none", len(results))
+	}
+}
+
+func TestSearchAllNoMatches(t *testing.T) {
+	if _, err := SearchAll([]int{1, 2, 3}, func(x int) bool { return x > 10 }, nil); !errors.Is(err, easyq.ErrNoMatches) {
+		t.Errorf("SearchAll() error = %v, want ErrNoMatches", err)
+	}
+	if _, err := SearchAll([]int{}, func(int) bool { return true }, nil); !errors.Is(err, easyq.ErrNoMatches) {
+		t.Errorf("SearchAll() on no items: error = %v, want ErrNoMatches", err)
+	}
+}
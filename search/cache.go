@@ -0,0 +1,335 @@
+package search
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// CacheOptions configures a Cache
+type CacheOptions struct {
+	// TTL is how long a cached search stays valid.
+	// If set to 0, entries do not expire.
+	TTL time.Duration
+
+	// MaxEntries is the maximum number of searches to cache. The least recently
+	// used entry is evicted to make room for a new one.
+	// If set to 0, will use the default (1024).
+	MaxEntries int
+}
+
+// CacheStats holds the counters of a Cache
+type CacheStats struct {
+	// Hits is the number of searches served from the cache
+	Hits uint64
+
+	// Misses is the number of cacheable searches that ran on the backend
+	Misses uint64
+
+	// Uncacheable is the number of searches that could not be cached, because
+	// their predicate is a Go function or their items hold values without a
+	// stable encoding, such as functions or channels
+	Uncacheable uint64
+
+	// Evictions is the number of entries evicted to respect MaxEntries
+	Evictions uint64
+
+	// Entries is the number of entries in the cache
+	Entries int
+}
+
+// Cache stores the results of searches, so that a search repeated over
+// unchanged items skips the backend. Searches use a cache attached to their
+// context with WithCache.
+//
+// Entries are keyed by a SHA-256 fingerprint of the items, the predicate, the
+// options and the session configuration. Only searches with a declarative
+// expr.Predicate can be cached, since a Go function has no stable identity.
+// ErrNoMatches is only cached when no item matches the predicate, as found by
+// evaluating it over the items, and not when the search missed existing matches.
+//
+// A Cache is safe for concurrent use.
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	lru     list.List // of *cacheEntry, most recently used first
+	stats   CacheStats
+}
+
+// cacheEntry is a cached search
+type cacheEntry struct {
+	key     [sha256.Size]byte
+	results []easyq.SearchResult
+	report  easyq.SearchReport
+	expires time.Time
+}
+
+// NewCache returns an empty cache. Options may be nil, in which case default
+// options are used.
+//
+// Example:
+//
+//	cache := search.NewCache(&search.CacheOptions{TTL: 10 * time.Minute, MaxEntries: 256})
+//	ctx := search.WithCache(context.Background(), cache)
+//	results, err := search.SearchContext(ctx, items, expr.Field("Status").Eq("open"), nil)
+func NewCache(options *CacheOptions) *Cache {
+	var opts CacheOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 1024
+	}
+
+	return &Cache{
+		ttl:        opts.TTL,
+		maxEntries: opts.MaxEntries,
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// Stats returns the counters of the cache
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Clear removes every entry from the cache. The counters are kept.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.lru.Init()
+}
+
+// get returns the cached results and report for key, if there are any and
+// they have not expired
+func (c *Cache) get(key [sha256.Size]byte) ([]easyq.SearchResult, *easyq.SearchReport, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok {
+		entry := element.Value.(*cacheEntry)
+		if entry.expires.IsZero() || time.Now().Before(entry.expires) {
+			c.lru.MoveToFront(element)
+			c.stats.Hits++
+			report := entry.report
+			return slices.Clone(entry.results), &report, true
+		}
+		c.lru.Remove(element)
+		delete(c.entries, key)
+	}
+
+	c.stats.Misses++
+	return nil, nil, false
+}
+
+// put caches the results and report of a search under key
+func (c *Cache) put(key [sha256.Size]byte, results []easyq.SearchResult, report *easyq.SearchReport) {
+	entry := &cacheEntry{
+		key:     key,
+		results: slices.Clone(results),
+		report:  *report,
+	}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// uncacheable records a search that could not be cached
+func (c *Cache) uncacheable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Uncacheable++
+}
+
+type cacheKey struct{}
+
+// WithCache returns a copy of ctx that makes searches in this package use the
+// given cache.
+func WithCache(ctx context.Context, cache *Cache) context.Context {
+	return context.WithValue(ctx, cacheKey{}, cache)
+}
+
+// cacheFromContext returns the cache attached to ctx with WithCache, if any
+func cacheFromContext(ctx context.Context) *Cache {
+	cache, _ := ctx.Value(cacheKey{}).(*Cache)
+	return cache
+}
+
+// fingerprint returns the cache key of a search, or false if the search cannot
+// be cached
+func fingerprint(config easyq.QuantumConnectionConfig, items interface{}, itemType reflect.Type, sp searchPredicate, opts easyq.SearchOptions) ([sha256.Size]byte, bool) {
+	if sp.expression == nil {
+		return [sha256.Size]byte{}, false
+	}
+
+	// JSON never contains a NUL byte, so it separates the parts unambiguously
+	hash := sha256.New()
+	for _, part := range []interface{}{config, itemType.PkgPath(), itemType.String(), *sp.expression, opts} {
+		data, err := json.Marshal(part)
+		if err != nil {
+			return [sha256.Size]byte{}, false
+		}
+		hash.Write(data)
+		hash.Write([]byte{0})
+	}
+
+	// Predicates can read fields that JSON leaves out, such as unexported
+	// fields, so the items are encoded from their reflected values
+	data, ok := appendValue(nil, reflect.ValueOf(items), nil)
+	if !ok {
+		return [sha256.Size]byte{}, false
+	}
+	hash.Write(data)
+
+	var key [sha256.Size]byte
+	hash.Sum(key[:0])
+	return key, true
+}
+
+// appendValue appends an unambiguous encoding of v to data, including every
+// field of structs whether exported or not. It reports false if v has no
+// stable encoding, because it holds functions, channels, unsafe pointers or a
+// cycle of pointers. visiting holds the pointers being encoded, to detect cycles.
+func appendValue(data []byte, v reflect.Value, visiting map[uintptr]bool) ([]byte, bool) {
+	if !v.IsValid() {
+		return append(data, 0), true
+	}
+
+	data = append(data, byte(v.Kind()))
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(data, 1), true
+		}
+		return append(data, 0), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.LittleEndian.AppendUint64(data, uint64(v.Int())), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.LittleEndian.AppendUint64(data, v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return binary.LittleEndian.AppendUint64(data, math.Float64bits(v.Float())), true
+	case reflect.Complex64, reflect.Complex128:
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(real(v.Complex())))
+		return binary.LittleEndian.AppendUint64(data, math.Float64bits(imag(v.Complex()))), true
+	case reflect.String:
+		data = binary.LittleEndian.AppendUint64(data, uint64(v.Len()))
+		return append(data, v.String()...), true
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(data, 0), true
+		}
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint64(data, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			var ok bool
+			if data, ok = appendValue(data, v.Index(i), visiting); !ok {
+				return nil, false
+			}
+		}
+		return data, true
+
+	case reflect.Struct:
+		data = binary.LittleEndian.AppendUint64(data, uint64(v.NumField()))
+		for i := 0; i < v.NumField(); i++ {
+			var ok bool
+			if data, ok = appendValue(data, v.Field(i), visiting); !ok {
+				return nil, false
+			}
+		}
+		return data, true
+
+	case reflect.Map:
+		if v.IsNil() {
+			return append(data, 0), true
+		}
+
+		// Map iteration order is random, so entries are sorted by their encoding
+		entries := make([][]byte, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entry, ok := appendValue(nil, iter.Key(), visiting)
+			if !ok {
+				return nil, false
+			}
+			if entry, ok = appendValue(entry, iter.Value(), visiting); !ok {
+				return nil, false
+			}
+			entries = append(entries, entry)
+		}
+		slices.SortFunc(entries, bytes.Compare)
+
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(entries)))
+		for _, entry := range entries {
+			data = binary.LittleEndian.AppendUint64(data, uint64(len(entry)))
+			data = append(data, entry...)
+		}
+		return data, true
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return append(data, 0), true
+		}
+		if visiting[v.Pointer()] {
+			return nil, false
+		}
+		if visiting == nil {
+			visiting = make(map[uintptr]bool)
+		}
+		visiting[v.Pointer()] = true
+		defer delete(visiting, v.Pointer())
+		return appendValue(append(data, 1), v.Elem(), visiting)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return append(data, 0), true
+		}
+		// The dynamic type matters, since predicates compare ints and floats differently
+		name := v.Elem().Type().PkgPath() + "." + v.Elem().Type().String()
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(name)))
+		return appendValue(append(data, name...), v.Elem(), visiting)
+	}
+
+	return nil, false
+}
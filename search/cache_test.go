@@ -0,0 +1,150 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/search/expr"
+	"github.com/Henrikarba/easyq-go/simulator"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *CacheOptions
+		wait     time.Duration
+		wantHit  bool
+		wantMiss uint64
+	}{
+		{"hit", nil, 0, true, 0},
+		{"hit before expiry", &CacheOptions{TTL: time.Hour}, 0, true, 0},
+		{"expired", &CacheOptions{TTL: time.Millisecond}, 5 * time.Millisecond, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCache(tt.options)
+			key := sha256.Sum256([]byte(tt.name))
+			results := []easyq.SearchResult{{Index: 3, Item: "x"}}
+			cache.put(key, results, &easyq.SearchReport{Iterations: 2})
+
+			time.Sleep(tt.wait)
+			cached, report, ok := cache.get(key)
+			if ok != tt.wantHit {
+				t.Fatalf("get() hit = %v, want %v", ok, tt.wantHit)
+			}
+			if ok && (len(cached) != 1 || cached[0].Index != 3 || report.Iterations != 2) {
+				t.Errorf("get() = %v, %+v, want the stored results and report", cached, report)
+			}
+
+			stats := cache.Stats()
+			if stats.Misses != tt.wantMiss {
+				t.Errorf("Misses = %d, want %d", stats.Misses, tt.wantMiss)
+			}
+			if !tt.wantHit && stats.Entries != 0 {
+				t.Errorf("Entries = %d, want expired entry removed", stats.Entries)
+			}
+		})
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	cache := NewCache(&CacheOptions{MaxEntries: 2})
+	keys := [3][sha256.Size]byte{sha256.Sum256([]byte("a")), sha256.Sum256([]byte("b")), sha256.Sum256([]byte("c"))}
+
+	cache.put(keys[0], nil, &easyq.SearchReport{})
+	cache.put(keys[1], nil, &easyq.SearchReport{})
+	cache.get(keys[0]) // keys[1] is now the least recently used
+	cache.put(keys[2], nil, &easyq.SearchReport{})
+
+	if _, _, ok := cache.get(keys[1]); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, _, ok := cache.get(keys[0]); !ok {
+		t.Error("recently used entry was evicted")
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("Stats() = %+v, want 1 eviction and 2 entries", stats)
+	}
+}
+
+type account struct {
+	Owner   string
+	balance int
+}
+
+func TestSearchCache(t *testing.T) {
+	cache := NewCache(nil)
+	ctx := WithCache(context.Background(), cache)
+	items := []account{{"a", 5}, {"b", -3}, {"c", 7}, {"d", 1}}
+	predicate := expr.Field("balance").Lt(0)
+
+	search := func() (*easyq.SearchReport, int) {
+		t.Helper()
+		results, report, err := SearchWithReportContext(ctx, items, predicate, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return report, results[0].Index
+	}
+
+	if report, index := search(); report.Cached || index != 1 {
+		t.Fatalf("first search: cached %v, index %d, want a backend search finding 1", report.Cached, index)
+	}
+	if report, index := search(); !report.Cached || index != 1 {
+		t.Fatalf("repeated search: cached %v, index %d, want a cache hit finding 1", report.Cached, index)
+	}
+
+	// Unexported fields are part of the fingerprint, since predicates can read them
+	items[1].balance, items[3].balance = 2, -8
+	if report, index := search(); report.Cached || index != 3 {
+		t.Fatalf("search of changed items: cached %v, index %d, want a backend search finding 3", report.Cached, index)
+	}
+
+	// A Go function predicate cannot be cached
+	if _, err := SearchContext(ctx, items, func(a account) bool { return a.Owner == "c" }, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Uncacheable != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit, 2 misses and 1 uncacheable", stats)
+	}
+}
+
+func TestSearchCacheNoMatches(t *testing.T) {
+	cache := NewCache(nil)
+	ctx := WithCache(context.Background(), cache)
+	items := []int{1, 2, 3}
+
+	for i := 0; i < 2; i++ {
+		_, report, err := SearchWithReportContext(ctx, items, expr.Item().Gt(10), nil)
+		if !errors.Is(err, easyq.ErrNoMatches) {
+			t.Fatalf("search %d: error = %v, want ErrNoMatches", i, err)
+		}
+		if report.Cached != (i == 1) {
+			t.Errorf("search %d: cached = %v", i, report.Cached)
+		}
+	}
+
+	// A search that misses existing matches is not cached, since running it
+	// again may find them
+	cache = NewCache(nil)
+	ctx = WithCache(testSession(t, stallingBackend{Backend: simulator.NewBackend()}), cache)
+	for i := 0; i < 2; i++ {
+		_, report, err := SearchWithReportContext(ctx, items, expr.Item().Gt(2), nil)
+		if !errors.Is(err, easyq.ErrNoMatches) {
+			t.Fatalf("missed search %d: error = %v, want ErrNoMatches", i, err)
+		}
+		if report.Cached {
+			t.Errorf("missed search %d was served from the cache", i)
+		}
+	}
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Misses != 2 {
+		t.Errorf("stats = %+v, want 2 misses and no entries", stats)
+	}
+}
@@ -0,0 +1,191 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/search/oracle"
+)
+
+// defaultChunkQubits sizes chunks for backends that do not report their qubit capacity
+const defaultChunkQubits = 20
+
+// FindChunked performs a quantum search on items too large for a single search
+// register. The items are split into chunks of options.ChunkSize items, sized
+// to the qubit capacity of the backend by default, and Grover's algorithm runs
+// on each chunk, up to options.Parallelism chunks at a time. Each result holds
+// its index in items. Options may be nil, in which case default options are used.
+//
+// Example:
+//
+//	opts := search.DefaultOptions()
+//	opts.Parallelism = runtime.NumCPU()
+//	results, err := search.FindChunked(records, func(r Record) bool { return r.Flagged }, &opts)
+func FindChunked[T any](items []T, predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], error) {
+	return FindChunkedContext(context.Background(), items, predicate, options)
+}
+
+// FindChunkedContext is like FindChunked but honours the deadline and cancellation of ctx.
+func FindChunkedContext[T any](ctx context.Context, items []T, predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], error) {
+	if len(items) == 0 {
+		return nil, errors.New("items cannot be empty")
+	}
+
+	return findChunks(ctx, predicate, options, func(chunkSize int) iter.Seq2[int, []T] {
+		return func(yield func(int, []T) bool) {
+			for offset := 0; offset < len(items); offset += chunkSize {
+				if !yield(offset, items[offset:min(offset+chunkSize, len(items))]) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// FindSeq is like FindChunked but searches the items produced by seq. Only the
+// chunks being searched are held in memory, so seq may produce more items than
+// fit in memory at once. Each result holds the position of its item in seq.
+//
+// Example:
+//
+//	results, err := search.FindSeq(slices.Values(records), isFlagged, nil)
+func FindSeq[T any](seq iter.Seq[T], predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], error) {
+	return FindSeqContext(context.Background(), seq, predicate, options)
+}
+
+// FindSeqContext is like FindSeq but honours the deadline and cancellation of ctx.
+func FindSeqContext[T any](ctx context.Context, seq iter.Seq[T], predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], error) {
+	if seq == nil {
+		return nil, errors.New("seq cannot be nil")
+	}
+
+	return findChunks(ctx, predicate, options, func(chunkSize int) iter.Seq2[int, []T] {
+		return func(yield func(int, []T) bool) {
+			offset := 0
+			var chunk []T
+			for item := range seq {
+				chunk = append(chunk, item)
+				if len(chunk) < chunkSize {
+					continue
+				}
+				if !yield(offset, chunk) {
+					return
+				}
+				offset += len(chunk)
+				chunk = nil
+			}
+			if len(chunk) > 0 {
+				yield(offset, chunk)
+			}
+		}
+	})
+}
+
+// findChunks searches each chunk produced by chunks, which yields the chunks
+// of at most chunkSize items together with the index of their first item, and
+// merges the results in chunk order
+func findChunks[T any](ctx context.Context, predicate func(T) bool, options *easyq.SearchOptions, chunks func(chunkSize int) iter.Seq2[int, []T]) ([]Result[T], error) {
+	if predicate == nil {
+		return nil, errors.New("predicate cannot be nil")
+	}
+
+	// Use default options if none provided
+	opts := DefaultOptions()
+	if options != nil {
+		opts = *options
+	}
+	if opts.ChunkSize < 0 {
+		return nil, errors.New("chunk size cannot be negative")
+	}
+
+	if opts.ChunkSize == 0 {
+		session, err := easyq.SessionFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		client, err := session.Client()
+		if err != nil {
+			return nil, err
+		}
+		qubits := client.QubitCapacity()
+		if qubits <= 0 {
+			qubits = defaultChunkQubits
+		}
+		opts.ChunkSize = 1 << min(qubits, oracle.MaxQubits)
+	}
+
+	parallelism := max(opts.Parallelism, 1)
+
+	// The first failure cancels the chunks still being searched
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		merged   [][]Result[T]
+	)
+	slots := make(chan struct{}, parallelism)
+
+	for offset, chunk := range chunks(opts.ChunkSize) {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		mu.Lock()
+		n := len(merged)
+		merged = append(merged, nil)
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results, err := FindContext(ctx, chunk, predicate, &opts)
+			if err != nil && !errors.Is(err, easyq.ErrNoMatches) {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+
+			// Map the chunk indices back to global indices
+			for i := range results {
+				results[i].Index += offset
+			}
+			mu.Lock()
+			merged[n] = results
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var results []Result[T]
+	for _, chunkResults := range merged {
+		results = append(results, chunkResults...)
+	}
+	if len(results) == 0 {
+		return nil, easyq.ErrNoMatches
+	}
+
+	return results, nil
+}
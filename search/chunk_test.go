@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+func TestFindChunked(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	// One match in each chunk of 16, including the short final chunk of 4
+	predicate := func(x int) bool { return x%16 == 5 || x == 98 }
+	want := []int{5, 21, 37, 53, 69, 85, 98}
+
+	for _, parallelism := range []int{1, 4} {
+		opts := DefaultOptions()
+		opts.ChunkSize = 16
+		opts.Parallelism = parallelism
+		opts.SamplingStrategy = easyq.FullScan
+
+		chunked, err := FindChunked(items, predicate, &opts)
+		if err != nil {
+			t.Fatalf("FindChunked with parallelism %d: %v", parallelism, err)
+		}
+		seq, err := FindSeq(slices.Values(items), predicate, &opts)
+		if err != nil {
+			t.Fatalf("FindSeq with parallelism %d: %v", parallelism, err)
+		}
+
+		for name, results := range map[string][]Result[int]{"FindChunked": chunked, "FindSeq": seq} {
+			indices := make([]int, len(results))
+			for i, result := range results {
+				if result.Item != items[result.Index] {
+					t.Errorf("%s: result %+v does not hold the item at its index", name, result)
+				}
+				indices[i] = result.Index
+			}
+			if !slices.Equal(indices, want) {
+				t.Errorf("%s with parallelism %d found indices %v, want %v in order", name, parallelism, indices, want)
+			}
+		}
+	}
+}
+
+func TestFindChunkedNoMatches(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ChunkSize = 8
+
+	items := make([]int, 20)
+	if _, err := FindChunked(items, func(x int) bool { return x > 0 }, &opts); !errors.Is(err, easyq.ErrNoMatches) {
+		t.Errorf("FindChunked() error = %v, want ErrNoMatches", err)
+	}
+	if _, err := FindChunked([]int{}, func(int) bool { return true }, &opts); err == nil {
+		t.Error("FindChunked on no items succeeded, want an error")
+	}
+
+	opts.ChunkSize = -1
+	if _, err := FindChunked(items, func(int) bool { return true }, &opts); err == nil {
+		t.Error("FindChunked with a negative chunk size succeeded, want an error")
+	}
+}
+
+func TestFindSeqCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// An endless sequence, so only cancellation ends the search
+	produced := 0
+	var seq iter.Seq[int] = func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			produced++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	predicate := func(x int) bool {
+		if x == 50 {
+			cancel()
+		}
+		return false
+	}
+
+	opts := DefaultOptions()
+	opts.ChunkSize = 16
+	opts.Parallelism = 2
+	if _, err := FindSeqContext(ctx, seq, predicate, &opts); !errors.Is(err, context.Canceled) {
+		t.Fatalf("FindSeqContext() error = %v, want context.Canceled", err)
+	}
+	if produced > 200 {
+		t.Errorf("sequence produced %d items after cancellation", produced)
+	}
+
+	items := make([]int, 100)
+	if _, err := FindChunkedContext(ctx, items, predicate, &opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("FindChunkedContext() with a canceled context: error = %v, want context.Canceled", err)
+	}
+}
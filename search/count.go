@@ -0,0 +1,109 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/bridge"
+)
+
+// MaxCountPrecision is the largest number of precision qubits accepted by Count
+const MaxCountPrecision = 24
+
+// Count estimates the number of items matching the predicate by quantum counting,
+// without searching for the matches themselves.
+//
+// Quantum counting runs phase estimation on the Grover operator with
+// precisionBits precision qubits. More precision gives tighter bounds at the
+// cost of more oracle calls (2^precisionBits - 1). If precisionBits is 0, a
+// default based on the number of items is used. The predicate is a function
+// or an expr.Predicate, as for Search.
+//
+// Example:
+//
+//	count, err := search.Count(items, predicate, 8)
+//	fmt.Printf("about %.0f matches (%d to %d with %.0f%% confidence)\n",
+//		count.Estimate, count.Lower, count.Upper, count.Confidence*100)
+func Count(items interface{}, predicate interface{}, precisionBits int) (*easyq.CountResult, error) {
+	return CountContext(context.Background(), items, predicate, precisionBits)
+}
+
+// CountContext is like Count but honours the deadline and cancellation of ctx.
+// Cancellation does not interrupt a native backend call that has already started.
+func CountContext(ctx context.Context, items interface{}, predicate interface{}, precisionBits int) (*easyq.CountResult, error) {
+	if precisionBits < 0 || precisionBits > MaxCountPrecision {
+		return nil, fmt.Errorf("precision must be between 0 and %d qubits", MaxCountPrecision)
+	}
+
+	itemsValue, sp, err := newSearchPredicate(items, predicate)
+	if err != nil {
+		return nil, err
+	}
+	size := itemsValue.Len()
+	if size == 0 {
+		return nil, errors.New("items cannot be empty")
+	}
+
+	// Resolve the session to run on, initializing if necessary
+	session, err := easyq.SessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := session.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluate the predicate to build the oracle
+	compileCircuit := session.Config().BackendType != easyq.Simulator
+	mappedPredicate, err := convertPredicate(ctx, items, size, itemsValue.Type().Elem(), sp, compileCircuit)
+	if err != nil {
+		return nil, err
+	}
+
+	return count(ctx, client, items, mappedPredicate, precisionBits)
+}
+
+// count performs a count through the bridge and converts its result
+func count(ctx context.Context, client *bridge.Client, items interface{}, mappedPredicate map[string]interface{}, precisionBits int) (*easyq.CountResult, error) {
+	rawResult, err := client.Count(ctx, items, mappedPredicate, map[string]interface{}{
+		"PrecisionBits": precisionBits,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the raw result to a CountResult
+	estimate, ok := rawResult["Estimate"].(float64)
+	if !ok {
+		return nil, errors.New("count result missing Estimate field")
+	}
+
+	result := &easyq.CountResult{
+		Estimate: estimate,
+		Lower:    int(estimate),
+		Upper:    int(estimate),
+	}
+	if lower, ok := rawResult["Lower"].(float64); ok {
+		result.Lower = int(lower)
+	}
+	if upper, ok := rawResult["Upper"].(float64); ok {
+		result.Upper = int(upper)
+	}
+	if confidence, ok := rawResult["Confidence"].(float64); ok {
+		result.Confidence = confidence
+	}
+	if bits, ok := rawResult["PrecisionBits"].(float64); ok {
+		result.PrecisionBits = int(bits)
+	}
+	if qubits, ok := rawResult["Qubits"].(float64); ok {
+		result.Qubits = int(qubits)
+	}
+	if calls, ok := rawResult["OracleCalls"].(float64); ok {
+		result.OracleCalls = int(calls)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package search
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	items := make([]int, 500)
+	for i := range items {
+		items[i] = i
+	}
+	multipleOf7 := func(x int) bool { return x%7 == 0 }
+	const want = 72
+
+	// The bounds hold with probability Confidence, so a few runs may miss
+	const runs = 20
+	covered := 0
+	for range runs {
+		result, err := Count(items, multipleOf7, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Lower > result.Upper {
+			t.Fatalf("Lower %d above Upper %d", result.Lower, result.Upper)
+		}
+		if result.PrecisionBits != 10 || result.OracleCalls != 1023 {
+			t.Errorf("PrecisionBits = %d, OracleCalls = %d, want 10 and 1023", result.PrecisionBits, result.OracleCalls)
+		}
+		if result.Lower <= want && want <= result.Upper {
+			covered++
+		}
+	}
+	if covered < runs/2 {
+		t.Errorf("bounds contain the true count in %d of %d runs", covered, runs)
+	}
+}
+
+func TestCountInvalidInputs(t *testing.T) {
+	always := func(int) bool { return true }
+	for _, precision := range []int{-1, MaxCountPrecision + 1} {
+		if _, err := Count([]int{1, 2, 3}, always, precision); err == nil {
+			t.Errorf("Count with precision %d succeeded, want an error", precision)
+		}
+	}
+	if _, err := Count([]int{}, always, 0); err == nil {
+		t.Error("Count on no items succeeded, want an error")
+	}
+}
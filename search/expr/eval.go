@@ -0,0 +1,462 @@
+package expr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Compile compiles the predicate to a Go function over items of type T.
+//
+// Field paths are checked against T as far as its static type allows; paths
+// through interface values are resolved for each item. An item whose field is
+// missing, nil or of an incompatible type does not match.
+//
+// Example:
+//
+//	adult, err := expr.Compile[User](expr.Field("Age").Ge(18))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(adult(User{Name: "Alice", Age: 34})) // true
+func Compile[T any](p Predicate) (func(T) bool, error) {
+	match, err := CompileType(p, reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(item T) bool {
+		return match(reflect.ValueOf(&item).Elem())
+	}, nil
+}
+
+// CompileType is like Compile for items whose type is only known at run time.
+// The returned function must be called with values of type itemType.
+func CompileType(p Predicate, itemType reflect.Type) (func(reflect.Value) bool, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return compile(p, itemType)
+}
+
+// matcher reports whether an item matches a compiled predicate
+type matcher func(item reflect.Value) bool
+
+// compile builds the matcher of a validated predicate
+func compile(p Predicate, itemType reflect.Type) (matcher, error) {
+	switch p.Op {
+	case OpAnd, OpOr:
+		args := make([]matcher, len(p.Args))
+		for i, arg := range p.Args {
+			m, err := compile(arg, itemType)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = m
+		}
+		all := p.Op == OpAnd
+		return func(item reflect.Value) bool {
+			for _, m := range args {
+				if m(item) != all {
+					return !all
+				}
+			}
+			return all
+		}, nil
+
+	case OpNot:
+		m, err := compile(p.Args[0], itemType)
+		if err != nil {
+			return nil, err
+		}
+		return func(item reflect.Value) bool {
+			return !m(item)
+		}, nil
+	}
+
+	var path []string
+	if p.Field != "" {
+		path = strings.Split(p.Field, ".")
+	}
+	fieldType, err := fieldType(itemType, path)
+	if err != nil {
+		return nil, fmt.Errorf("expr: field %q: %w", p.Field, err)
+	}
+
+	switch p.Op {
+	case OpBetween:
+		min, _ := newOperand(p.Min)
+		max, _ := newOperand(p.Max)
+		if err := checkOperand(p.Op, fieldType, min); err != nil {
+			return nil, fmt.Errorf("expr: field %q: %w", p.Field, err)
+		}
+		return func(item reflect.Value) bool {
+			v, ok := resolve(item, path)
+			if !ok || v.Kind() == reflect.Bool {
+				return false
+			}
+			low, ok := compareValue(v, min)
+			if !ok || low < 0 {
+				return false
+			}
+			high, ok := compareValue(v, max)
+			return ok && high <= 0
+		}, nil
+
+	case OpMasked:
+		value, _ := newOperand(p.Value)
+		if fieldType != nil && !isInteger(fieldType.Kind()) {
+			return nil, fmt.Errorf("expr: field %q: Masked requires an integer field, got %s", p.Field, fieldType)
+		}
+		want, _ := value.uint()
+		return func(item reflect.Value) bool {
+			v, ok := resolve(item, path)
+			if !ok {
+				return false
+			}
+			switch {
+			case isInteger(v.Kind()) && v.CanInt():
+				return uint64(v.Int())&p.Mask == want
+			case isInteger(v.Kind()):
+				return v.Uint()&p.Mask == want
+			}
+			return false
+		}, nil
+
+	case OpHasPrefix, OpHasSuffix, OpContains:
+		value := p.Value.(string)
+		if fieldType != nil && fieldType.Kind() != reflect.String {
+			return nil, fmt.Errorf("expr: field %q: %s requires a string field, got %s", p.Field, p.Op, fieldType)
+		}
+		check := map[Op]func(s, substr string) bool{
+			OpHasPrefix: strings.HasPrefix,
+			OpHasSuffix: strings.HasSuffix,
+			OpContains:  strings.Contains,
+		}[p.Op]
+		return func(item reflect.Value) bool {
+			v, ok := resolve(item, path)
+			return ok && v.Kind() == reflect.String && check(v.String(), value)
+		}, nil
+
+	default:
+		value, _ := newOperand(p.Value)
+		if err := checkOperand(p.Op, fieldType, value); err != nil {
+			return nil, fmt.Errorf("expr: field %q: %w", p.Field, err)
+		}
+		test := map[Op]func(c int) bool{
+			OpEq: func(c int) bool { return c == 0 },
+			OpNe: func(c int) bool { return c != 0 },
+			OpLt: func(c int) bool { return c < 0 },
+			OpLe: func(c int) bool { return c <= 0 },
+			OpGt: func(c int) bool { return c > 0 },
+			OpGe: func(c int) bool { return c >= 0 },
+		}[p.Op]
+		ordered := p.Op != OpEq && p.Op != OpNe
+		return func(item reflect.Value) bool {
+			v, ok := resolve(item, path)
+			if !ok || (ordered && v.Kind() == reflect.Bool) {
+				return false
+			}
+			c, ok := compareValue(v, value)
+			return ok && test(c)
+		}, nil
+	}
+}
+
+// fieldType returns the static type of the field at path, or nil if the path
+// passes through an interface and can only be resolved for each item
+func fieldType(t reflect.Type, path []string) (reflect.Type, error) {
+	for _, name := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Interface:
+			return nil, nil
+		case reflect.Struct:
+			field, ok := t.FieldByName(name)
+			if !ok {
+				return nil, fmt.Errorf("%s has no field %s", t, name)
+			}
+			t = field.Type
+		case reflect.Map:
+			if t.Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("%s does not have string keys", t)
+			}
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("cannot select %s from %s", name, t)
+		}
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// resolve returns the value of the field at path, following pointers and
+// interfaces. It reports false if the field is missing or nil.
+func resolve(v reflect.Value, path []string) (reflect.Value, bool) {
+	v, ok := indirect(v)
+	for _, name := range path {
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(name)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		default:
+			return reflect.Value{}, false
+		}
+
+		v, ok = indirect(v)
+	}
+	return v, ok
+}
+
+// indirect follows pointers and interfaces, reporting false for nil
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+// operandKind is the type of a comparison operand
+type operandKind int
+
+const (
+	numberOperand operandKind = iota
+	stringOperand
+	boolOperand
+)
+
+// operand is a normalized comparison operand
+type operand struct {
+	kind operandKind
+
+	// For numbers, f holds the value. If exact is set, i holds it exactly, or
+	// u does if unsigned is set, for integers above math.MaxInt64.
+	f        float64
+	i        int64
+	u        uint64
+	exact    bool
+	unsigned bool
+
+	s string
+	b bool
+}
+
+// newOperand normalizes a comparison value, which may have passed through JSON
+func newOperand(value interface{}) (operand, error) {
+	switch v := value.(type) {
+	case nil:
+		return operand{}, errors.New("expr: missing comparison value")
+	case string:
+		return operand{kind: stringOperand, s: v}, nil
+	case bool:
+		return operand{kind: boolOperand, b: v}, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return intOperand(i), nil
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return uintOperand(u), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return operand{}, fmt.Errorf("expr: invalid number %q", v)
+		}
+		return floatOperand(f), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intOperand(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintOperand(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return floatOperand(rv.Float()), nil
+	case reflect.String:
+		return operand{kind: stringOperand, s: rv.String()}, nil
+	case reflect.Bool:
+		return operand{kind: boolOperand, b: rv.Bool()}, nil
+	}
+	return operand{}, fmt.Errorf("expr: unsupported comparison value of type %T", value)
+}
+
+func intOperand(i int64) operand {
+	return operand{kind: numberOperand, f: float64(i), i: i, exact: true}
+}
+
+func uintOperand(u uint64) operand {
+	if u <= math.MaxInt64 {
+		return intOperand(int64(u))
+	}
+	return operand{kind: numberOperand, f: float64(u), u: u, exact: true, unsigned: true}
+}
+
+func floatOperand(f float64) operand {
+	// Integral floats, as produced by JSON, compare exactly with integer fields
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return intOperand(int64(f))
+	}
+	if f == math.Trunc(f) && f >= 0 && f < 1<<64 {
+		return uintOperand(uint64(f))
+	}
+	return operand{kind: numberOperand, f: f}
+}
+
+// uint returns the value of an exact, non-negative operand
+func (o operand) uint() (uint64, bool) {
+	switch {
+	case !o.exact || (!o.unsigned && o.i < 0):
+		return 0, false
+	case o.unsigned:
+		return o.u, true
+	}
+	return uint64(o.i), true
+}
+
+// checkOperand checks that a field of type t can be compared with value by op.
+// A nil t is only known at run time and is not checked.
+func checkOperand(op Op, t reflect.Type, value operand) error {
+	if t == nil {
+		return nil
+	}
+
+	switch {
+	case isNumber(t.Kind()):
+		if value.kind == numberOperand {
+			return nil
+		}
+	case t.Kind() == reflect.String:
+		if value.kind == stringOperand {
+			return nil
+		}
+	case t.Kind() == reflect.Bool:
+		if value.kind == boolOperand {
+			if op != OpEq && op != OpNe {
+				return fmt.Errorf("%s is not supported for bool fields", op)
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("%s fields cannot be compared", t)
+	}
+	return fmt.Errorf("cannot compare %s field with %s", t, value.describe())
+}
+
+// compareValue compares v with value, returning -1, 0 or +1.
+// It reports false if they are not comparable.
+func compareValue(v reflect.Value, value operand) (int, bool) {
+	switch {
+	case value.kind == numberOperand && isNumber(v.Kind()):
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			switch {
+			case value.unsigned:
+				return -1, true
+			case value.exact:
+				return compareOrdered(v.Int(), value.i), true
+			}
+			return compareFloat(float64(v.Int()), value.f)
+		case reflect.Float32, reflect.Float64:
+			return compareFloat(v.Float(), value.f)
+		default:
+			if value.exact {
+				u, ok := value.uint()
+				if !ok {
+					return 1, true
+				}
+				return compareOrdered(v.Uint(), u), true
+			}
+			return compareFloat(float64(v.Uint()), value.f)
+		}
+
+	case value.kind == stringOperand && v.Kind() == reflect.String:
+		return strings.Compare(v.String(), value.s), true
+
+	case value.kind == boolOperand && v.Kind() == reflect.Bool:
+		if v.Bool() == value.b {
+			return 0, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func compareOrdered[N int64 | uint64](a, b N) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) (int, bool) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0, false
+	}
+	switch {
+	case a < b:
+		return -1, true
+	case a > b:
+		return 1, true
+	}
+	return 0, true
+}
+
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// describe returns a description of the operand for error messages
+func (o operand) describe() string {
+	switch o.kind {
+	case stringOperand:
+		return fmt.Sprintf("string %q", o.s)
+	case boolOperand:
+		return fmt.Sprintf("bool %v", o.b)
+	}
+	if o.unsigned {
+		return fmt.Sprintf("number %d", o.u)
+	}
+	return fmt.Sprintf("number %v", o.f)
+}
@@ -0,0 +1,122 @@
+package expr
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+type address struct {
+	City string
+	Zip  *int
+}
+
+type user struct {
+	Name    string
+	Age     int
+	Score   float64
+	Flags   uint64
+	Active  bool
+	Address *address
+	Tags    map[string]string
+	Extra   interface{}
+	secret  int
+}
+
+func TestCompile(t *testing.T) {
+	zip := 10115
+	alice := user{
+		Name:    "Alice",
+		Age:     34,
+		Score:   7.5,
+		Flags:   math.MaxUint64 - 1,
+		Active:  true,
+		Address: &address{City: "Tallinn", Zip: &zip},
+		Tags:    map[string]string{"team": "core"},
+		Extra:   map[string]interface{}{"level": 3.0},
+		secret:  42,
+	}
+	bob := user{Name: "Bob", Age: -1, Score: math.NaN()}
+
+	tests := []struct {
+		name      string
+		predicate Predicate
+		item      user
+		want      bool
+	}{
+		{"equal", Field("Name").Eq("Alice"), alice, true},
+		{"not equal", Field("Name").Ne("Alice"), bob, true},
+		{"int with float", Field("Age").Lt(34.5), alice, true},
+		{"int with integral float", Field("Age").Eq(34.0), alice, true},
+		{"float with int", Field("Score").Gt(7), alice, true},
+		{"NaN never compares", Field("Score").Ne(1.0), bob, false},
+		{"between inclusive", Field("Age").Between(34, 40), alice, true},
+		{"between mixed bounds", Field("Score").Between(7, 7.5), alice, true},
+		{"negative against unsigned", Field("Flags").Gt(-1), bob, true},
+		{"unsigned above MaxInt64", Field("Flags").Eq(uint64(math.MaxUint64 - 1)), alice, true},
+		{"unsigned above MaxInt64 with int field", Field("Age").Lt(uint64(math.MaxUint64)), alice, true},
+		{"has bits", Field("Flags").HasBits(1 << 63), alice, true},
+		{"masked", Field("Flags").Masked(1, 0), alice, true},
+		{"bool equal", Field("Active").Eq(true), alice, true},
+		{"nested pointer", Field("Address.City").HasPrefix("Tall"), alice, true},
+		{"nil pointer does not match", Field("Address.City").Eq("Tallinn"), bob, false},
+		{"nil pointer does not match Ne", Field("Address.City").Ne("Tallinn"), bob, false},
+		{"double pointer", Field("Address.Zip").Eq(10115), alice, true},
+		{"map key", Field("Tags.team").Contains("or"), alice, true},
+		{"missing map key", Field("Tags.owner").Eq("x"), alice, false},
+		{"interface field", Field("Extra.level").Ge(3), alice, true},
+		{"nil interface field", Field("Extra.level").Ge(3), bob, false},
+		{"unexported field", Field("secret").Eq(42), alice, true},
+		{"empty and", And(), bob, true},
+		{"empty or", Or(), alice, false},
+		{"not", Not(Field("Active").Eq(true)), bob, true},
+		{"nested logic", Or(And(Field("Age").Gt(30), Field("Name").HasSuffix("ce")), Field("Age").Lt(0)), bob, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := Compile[user](tt.predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := match(tt.item); got != tt.want {
+				t.Errorf("%v on %s = %v, want %v", tt.predicate, tt.item.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate Predicate
+	}{
+		{"unknown field", Field("Email").Eq("x")},
+		{"string with number", Field("Name").Eq(3)},
+		{"number with string", Field("Age").Gt("old")},
+		{"ordered bool", Field("Active").Lt(true)},
+		{"masked float", Field("Score").Masked(1, 1)},
+		{"prefix of number", Field("Age").HasPrefix("3")},
+		{"field of string", Field("Name.Length").Eq(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile[user](tt.predicate); err == nil {
+				t.Errorf("Compile(%v) succeeded, want an error", tt.predicate)
+			}
+		})
+	}
+}
+
+func TestCompileType(t *testing.T) {
+	match, err := CompileType(Item().Between(10, 20), reflect.TypeFor[int]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, want := range map[int]bool{9: false, 10: true, 15: true, 20: true, 21: false} {
+		if got := match(reflect.ValueOf(value)); got != want {
+			t.Errorf("Between(10, 20) on %d = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package expr builds declarative search predicates from field comparisons,
+// string checks, numeric ranges and boolean combinators.
+//
+// Unlike a Go function, a Predicate can be serialized to JSON and sent to any
+// backend, including remote quantum services. It can also be compiled to a Go
+// evaluator with Compile.
+//
+// Example:
+//
+//	// Adults whose name starts with "A"
+//	p := expr.And(
+//		expr.Field("Age").Ge(18),
+//		expr.Field("Name").HasPrefix("A"),
+//	)
+//	results, err := search.Search(users, p, nil)
+package expr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Op identifies the operation of a Predicate
+type Op string
+
+const (
+	// OpEq matches when the field equals Value
+	OpEq Op = "Eq"
+
+	// OpNe matches when the field does not equal Value
+	OpNe Op = "Ne"
+
+	// OpLt matches when the field is less than Value
+	OpLt Op = "Lt"
+
+	// OpLe matches when the field is less than or equal to Value
+	OpLe Op = "Le"
+
+	// OpGt matches when the field is greater than Value
+	OpGt Op = "Gt"
+
+	// OpGe matches when the field is greater than or equal to Value
+	OpGe Op = "Ge"
+
+	// OpBetween matches when the field is between Min and Max (inclusive)
+	OpBetween Op = "Between"
+
+	// OpHasPrefix matches when the string field starts with Value
+	OpHasPrefix Op = "HasPrefix"
+
+	// OpHasSuffix matches when the string field ends with Value
+	OpHasSuffix Op = "HasSuffix"
+
+	// OpContains matches when the string field contains Value
+	OpContains Op = "Contains"
+
+	// OpMasked matches when the integer field, masked with Mask, equals Value
+	OpMasked Op = "Masked"
+
+	// OpAnd matches when all Args match
+	OpAnd Op = "And"
+
+	// OpOr matches when any of Args matches
+	OpOr Op = "Or"
+
+	// OpNot matches when its single argument does not match
+	OpNot Op = "Not"
+)
+
+// Predicate is a serializable search predicate.
+//
+// Predicates are normally built with Field, And, Or and Not rather than
+// filled in directly. The zero Predicate is invalid.
+type Predicate struct {
+	// Op is the operation of the predicate
+	Op Op
+
+	// Field is the dot-separated path of the compared field, such as
+	// "Address.City". An empty path compares the item itself.
+	Field string `json:",omitempty"`
+
+	// Value is the operand of comparisons and string checks
+	Value interface{} `json:",omitempty"`
+
+	// Min and Max are the inclusive bounds of OpBetween
+	Min interface{} `json:",omitempty"`
+	Max interface{} `json:",omitempty"`
+
+	// Mask selects the bits tested by OpMasked
+	Mask uint64 `json:",omitempty"`
+
+	// Args are the operands of OpAnd, OpOr and OpNot
+	Args []Predicate `json:",omitempty"`
+}
+
+// FieldRef refers to a field of the searched items. Its methods build
+// predicates comparing the field.
+type FieldRef struct {
+	path string
+}
+
+// Field returns a reference to the field at the given dot-separated path.
+// Struct fields are looked up by name, and maps with string keys by key.
+func Field(path string) FieldRef {
+	return FieldRef{path: path}
+}
+
+// Item returns a reference to the searched item itself, for searching
+// slices of strings or numbers.
+func Item() FieldRef {
+	return FieldRef{}
+}
+
+// Eq matches items whose field equals value.
+func (f FieldRef) Eq(value interface{}) Predicate {
+	return Predicate{Op: OpEq, Field: f.path, Value: value}
+}
+
+// Ne matches items whose field does not equal value.
+func (f FieldRef) Ne(value interface{}) Predicate {
+	return Predicate{Op: OpNe, Field: f.path, Value: value}
+}
+
+// Lt matches items whose field is less than value.
+func (f FieldRef) Lt(value interface{}) Predicate {
+	return Predicate{Op: OpLt, Field: f.path, Value: value}
+}
+
+// Le matches items whose field is less than or equal to value.
+func (f FieldRef) Le(value interface{}) Predicate {
+	return Predicate{Op: OpLe, Field: f.path, Value: value}
+}
+
+// Gt matches items whose field is greater than value.
+func (f FieldRef) Gt(value interface{}) Predicate {
+	return Predicate{Op: OpGt, Field: f.path, Value: value}
+}
+
+// Ge matches items whose field is greater than or equal to value.
+func (f FieldRef) Ge(value interface{}) Predicate {
+	return Predicate{Op: OpGe, Field: f.path, Value: value}
+}
+
+// Between matches items whose field is between min and max (inclusive).
+func (f FieldRef) Between(min, max interface{}) Predicate {
+	return Predicate{Op: OpBetween, Field: f.path, Min: min, Max: max}
+}
+
+// HasPrefix matches items whose string field starts with prefix.
+func (f FieldRef) HasPrefix(prefix string) Predicate {
+	return Predicate{Op: OpHasPrefix, Field: f.path, Value: prefix}
+}
+
+// HasSuffix matches items whose string field ends with suffix.
+func (f FieldRef) HasSuffix(suffix string) Predicate {
+	return Predicate{Op: OpHasSuffix, Field: f.path, Value: suffix}
+}
+
+// Contains matches items whose string field contains substr.
+func (f FieldRef) Contains(substr string) Predicate {
+	return Predicate{Op: OpContains, Field: f.path, Value: substr}
+}
+
+// Masked matches items whose integer field has the given value in the bits
+// selected by mask, that is field&mask == value.
+func (f FieldRef) Masked(mask, value uint64) Predicate {
+	return Predicate{Op: OpMasked, Field: f.path, Mask: mask, Value: value}
+}
+
+// HasBits matches items whose integer field has all the bits of mask set.
+func (f FieldRef) HasBits(mask uint64) Predicate {
+	return f.Masked(mask, mask)
+}
+
+// And matches items that match all of the given predicates.
+// With no predicates, it matches every item.
+func And(predicates ...Predicate) Predicate {
+	return Predicate{Op: OpAnd, Args: predicates}
+}
+
+// Or matches items that match any of the given predicates.
+// With no predicates, it matches no item.
+func Or(predicates ...Predicate) Predicate {
+	return Predicate{Op: OpOr, Args: predicates}
+}
+
+// Not matches items that do not match the given predicate.
+func Not(predicate Predicate) Predicate {
+	return Predicate{Op: OpNot, Args: []Predicate{predicate}}
+}
+
+// String returns the JSON form of the predicate
+func (p Predicate) String() string {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Sprintf("invalid predicate: %v", err)
+	}
+	return string(data)
+}
+
+// UnmarshalJSON decodes a predicate, keeping numeric operands exact
+func (p *Predicate) UnmarshalJSON(data []byte) error {
+	// Decode into a type without this method to avoid recursion
+	type plain Predicate
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded plain
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+
+	*p = Predicate(decoded)
+	return nil
+}
+
+// Parse decodes a predicate from its JSON form and checks that it is well formed.
+func Parse(data []byte) (Predicate, error) {
+	var p Predicate
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Predicate{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return Predicate{}, err
+	}
+	return p, nil
+}
+
+// Validate checks that the predicate is well formed, independently of the
+// type of the items it will be applied to.
+func (p Predicate) Validate() error {
+	switch p.Op {
+	case OpEq, OpNe, OpLt, OpLe, OpGt, OpGe:
+		_, err := newOperand(p.Value)
+		return err
+
+	case OpBetween:
+		min, err := newOperand(p.Min)
+		if err != nil {
+			return err
+		}
+		max, err := newOperand(p.Max)
+		if err != nil {
+			return err
+		}
+		if min.kind != max.kind {
+			return fmt.Errorf("expr: Between bounds have different types (%v and %v)", p.Min, p.Max)
+		}
+		return nil
+
+	case OpHasPrefix, OpHasSuffix, OpContains:
+		if _, ok := p.Value.(string); !ok {
+			return fmt.Errorf("expr: %s requires a string value, got %T", p.Op, p.Value)
+		}
+		return nil
+
+	case OpMasked:
+		value, err := newOperand(p.Value)
+		if err != nil {
+			return err
+		}
+		if _, ok := value.uint(); !ok {
+			return fmt.Errorf("expr: Masked requires a non-negative integer value, got %v", p.Value)
+		}
+		return nil
+
+	case OpAnd, OpOr:
+		for _, arg := range p.Args {
+			if err := arg.Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case OpNot:
+		if len(p.Args) != 1 {
+			return fmt.Errorf("expr: Not requires exactly one argument, got %d", len(p.Args))
+		}
+		return p.Args[0].Validate()
+
+	case "":
+		return errors.New("expr: empty predicate")
+
+	default:
+		return fmt.Errorf("expr: unknown operation %q", p.Op)
+	}
+}
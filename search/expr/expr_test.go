@@ -0,0 +1,96 @@
+package expr
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate Predicate
+		wantErr   bool
+	}{
+		{"comparison", Field("Age").Gt(3), false},
+		{"unsigned above MaxInt64", Field("Flags").Eq(uint64(math.MaxUint64)), false},
+		{"missing value", Predicate{Op: OpEq, Field: "Age"}, true},
+		{"unsupported value", Field("Age").Eq([]int{1}), true},
+		{"between", Field("Age").Between(1, 2.5), false},
+		{"between different kinds", Field("Age").Between(1, "z"), true},
+		{"between missing bound", Predicate{Op: OpBetween, Field: "Age", Min: 1}, true},
+		{"prefix", Field("Name").HasPrefix("A"), false},
+		{"prefix of number", Predicate{Op: OpHasPrefix, Field: "Name", Value: 1}, true},
+		{"masked", Field("Flags").Masked(0xff, 0x0f), false},
+		{"masked above MaxInt64", Field("Flags").HasBits(1 << 63), false},
+		{"masked negative", Predicate{Op: OpMasked, Field: "Flags", Mask: 1, Value: -1}, true},
+		{"masked fraction", Predicate{Op: OpMasked, Field: "Flags", Mask: 1, Value: 0.5}, true},
+		{"and", And(Field("Age").Gt(3), Field("Name").Eq("x")), false},
+		{"and with invalid argument", And(Field("Age").Gt(3), Predicate{}), true},
+		{"not", Not(Field("Age").Gt(3)), false},
+		{"not without argument", Predicate{Op: OpNot}, true},
+		{"empty", Predicate{}, true},
+		{"unknown operation", Predicate{Op: "Like", Field: "Name", Value: "x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.predicate.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		item user
+		want bool
+	}{
+		{"comparison", `{"Op":"Gt","Field":"Age","Value":30}`, user{Age: 34}, true},
+		{"exact large integer", `{"Op":"Eq","Field":"Flags","Value":18446744073709551614}`, user{Flags: math.MaxUint64 - 1}, true},
+		{"large integer differs", `{"Op":"Eq","Field":"Flags","Value":18446744073709551614}`, user{Flags: math.MaxUint64}, false},
+		{"masked", `{"Op":"Masked","Field":"Flags","Mask":3,"Value":2}`, user{Flags: 6}, true},
+		{"logic", `{"Op":"Or","Args":[{"Op":"Eq","Field":"Name","Value":"x"},{"Op":"Lt","Field":"Score","Value":1.5}]}`, user{Score: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			match, err := Compile[user](p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := match(tt.item); got != tt.want {
+				t.Errorf("%s on %+v = %v, want %v", tt.json, tt.item, got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{`{"Op":"Eq","Field":"Age"}`, `{"Op":`, `{"Op":"Not","Args":[]}`} {
+		if _, err := Parse([]byte(invalid)); err == nil {
+			t.Errorf("Parse(%s) succeeded, want an error", invalid)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	p := And(Field("Flags").HasBits(1<<63), Not(Field("Name").Contains("x")), Field("Age").Between(-3, 7))
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.String() != p.String() {
+		t.Errorf("round trip = %s, want %s", decoded, p)
+	}
+}
@@ -0,0 +1,138 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// SearchCSV performs a quantum search over the records of CSV data. The first
+// record is the header, and each following record is searched as a
+// map[string]string from column name to field. The predicate is either a
+// func(map[string]string) bool or an expr.Predicate over the column names.
+// Fields are strings, so declarative predicates should compare them with
+// string values. Each result holds the record as its Item and the line number
+// where the record starts as its Key. Options may be nil, in which case
+// default options are used.
+//
+// Example:
+//
+//	f, err := os.Open("users.csv")
+//	defer f.Close()
+//	results, err := search.SearchCSV(f, expr.Field("country").Eq("EE"), nil)
+//	for _, r := range results {
+//		fmt.Printf("line %d: %s\n", r.Key, r.Item.(map[string]string)["name"])
+//	}
+func SearchCSV(r io.Reader, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	return SearchCSVContext(context.Background(), r, predicate, options)
+}
+
+// SearchCSVContext is like SearchCSV but honours the deadline and cancellation of ctx.
+func SearchCSVContext(ctx context.Context, r io.Reader, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV data has no header")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]string
+	var lines []int
+	for {
+		if len(records)%predicateCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := make(map[string]string, len(header))
+		for i, name := range header {
+			record[name] = fields[i]
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	_, sp, err := newSearchPredicate(records, predicate)
+	if err != nil {
+		return nil, err
+	}
+	return searchKeyed(ctx, records, sp, func(i int) interface{} { return lines[i] }, options)
+}
+
+// SearchJSONL performs a quantum search over JSON Lines data, in which each
+// non-blank line holds a JSON object. Each object is searched as the
+// map[string]interface{} decoded by encoding/json, so numbers are float64. The
+// predicate is either a func(map[string]interface{}) bool or an expr.Predicate
+// over the object's fields. Each result holds the object as its Item and its
+// line number as its Key. Options may be nil, in which case default options
+// are used.
+//
+// Example:
+//
+//	results, err := search.SearchJSONL(f, expr.Field("status").Eq("failed"), nil)
+func SearchJSONL(r io.Reader, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	return SearchJSONLContext(context.Background(), r, predicate, options)
+}
+
+// SearchJSONLContext is like SearchJSONL but honours the deadline and cancellation of ctx.
+func SearchJSONLContext(ctx context.Context, r io.Reader, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	reader := bufio.NewReader(r)
+	var objects []map[string]interface{}
+	var lines []int
+	for line := 1; ; line++ {
+		if line%predicateCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			var object map[string]interface{}
+			if err := json.Unmarshal(data, &object); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			objects = append(objects, object)
+			lines = append(lines, line)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	_, sp, err := newSearchPredicate(objects, predicate)
+	if err != nil {
+		return nil, err
+	}
+	return searchKeyed(ctx, objects, sp, func(i int) interface{} { return lines[i] }, options)
+}
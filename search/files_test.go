@@ -0,0 +1,83 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Henrikarba/easyq-go/search/expr"
+)
+
+func TestSearchCSV(t *testing.T) {
+	// The note of alice spans two lines, so later records start one line further down
+	data := "name,note,country\n" +
+		"alice,\"likes\ncats\",EE\n" +
+		"bob,plain,FI\n" +
+		"carol,\"says \"\"hi\"\"\",EE\n"
+
+	tests := []struct {
+		name      string
+		predicate interface{}
+		wantName  string
+		wantLine  int
+	}{
+		{"function", func(record map[string]string) bool { return record["country"] == "FI" }, "bob", 4},
+		{"expression", expr.Field("name").Eq("carol"), "carol", 5},
+		{"multi-line field", func(record map[string]string) bool { return record["note"] == "likes\ncats" }, "alice", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := SearchCSV(strings.NewReader(data), tt.predicate, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			record := results[0].Item.(map[string]string)
+			if record["name"] != tt.wantName || results[0].Key != tt.wantLine {
+				t.Errorf("SearchCSV() = %s at line %v, want %s at line %d", record["name"], results[0].Key, tt.wantName, tt.wantLine)
+			}
+		})
+	}
+
+	if _, err := SearchCSV(strings.NewReader(""), func(map[string]string) bool { return true }, nil); err == nil {
+		t.Error("SearchCSV on empty data succeeded, want an error")
+	}
+}
+
+func TestSearchJSONL(t *testing.T) {
+	// Blank lines are skipped but still counted, and the last line has no newline
+	data := "{\"id\": 1}\n\n   \n{\"id\": 2, \"status\": \"failed\"}\n{\"id\": 3}"
+
+	tests := []struct {
+		name      string
+		predicate interface{}
+		wantID    float64
+		wantLine  int
+	}{
+		{"expression", expr.Field("status").Eq("failed"), 2, 4},
+		{"function", func(object map[string]interface{}) bool { return object["id"] == 3.0 }, 3, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := SearchJSONL(strings.NewReader(data), tt.predicate, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			object := results[0].Item.(map[string]interface{})
+			if object["id"] != tt.wantID || results[0].Key != tt.wantLine {
+				t.Errorf("SearchJSONL() = id %v at line %v, want id %v at line %d", object["id"], results[0].Key, tt.wantID, tt.wantLine)
+			}
+		})
+	}
+
+	_, err := SearchJSONL(strings.NewReader("{\"id\": 1}\n\n{oops}\n"), func(map[string]interface{}) bool { return true }, nil)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("SearchJSONL() error = %v, want a decoding error for line 3", err)
+	}
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// Result is a typed match returned by Find
+type Result[T any] struct {
+	// Item is the matching element of the searched slice
+	Item T
+
+	// Index is the position of the item in the searched slice
+	Index int
+
+	// Probability is the probability of measuring this item in a single shot
+	// of the search circuit, if reported by the backend
+	Probability float64
+}
+
+// Find performs a quantum search on the given items using the provided predicate.
+// It is the type-safe counterpart of Search: the predicate is checked at compile
+// time, and each result holds the original element rather than a copy that has
+// passed through the bridge. Options may be nil, in which case default options are used.
+//
+// Example:
+//
+//	type User struct {
+//		Name string
+//		Age  int
+//	}
+//	users := []User{{"Alice", 34}, {"Bob", 17}, {"Carol", 52}}
+//	results, err := search.Find(users, func(u User) bool { return u.Age > 40 }, nil)
+//	// results[0].Item is a User
+func Find[T any](items []T, predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], error) {
+	return FindContext(context.Background(), items, predicate, options)
+}
+
+// FindContext is like Find but honours the deadline and cancellation of ctx.
+func FindContext[T any](ctx context.Context, items []T, predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], error) {
+	results, _, err := FindWithReportContext(ctx, items, predicate, options)
+	return results, err
+}
+
+// FindWithReport is like Find but also returns a report with statistics about
+// the search. The report is also returned with ErrNoMatches.
+func FindWithReport[T any](items []T, predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], *easyq.SearchReport, error) {
+	return FindWithReportContext(context.Background(), items, predicate, options)
+}
+
+// FindWithReportContext is like FindWithReport but honours the deadline and
+// cancellation of ctx.
+func FindWithReportContext[T any](ctx context.Context, items []T, predicate func(T) bool, options *easyq.SearchOptions) ([]Result[T], *easyq.SearchReport, error) {
+	if predicate == nil {
+		return nil, nil, errors.New("predicate cannot be nil")
+	}
+
+	matches := func(i int) bool {
+		return predicate(items[i])
+	}
+
+	raw, report, err := search(ctx, items, len(items), reflect.TypeFor[T](), searchPredicate{matches: matches}, options)
+	if err != nil {
+		return nil, report, err
+	}
+
+	// Map the indices back to the original elements
+	results := make([]Result[T], 0, len(raw))
+	for _, r := range raw {
+		if r.Index < 0 || r.Index >= len(items) {
+			return nil, nil, fmt.Errorf("result index %d out of range [0, %d)", r.Index, len(items))
+		}
+		results = append(results, Result[T]{Item: items[r.Index], Index: r.Index, Probability: r.Probability})
+	}
+
+	return results, report, nil
+}
+
+// FindOne performs a quantum search and returns the first matching item.
+// This is more efficient than Find when only one result is needed.
+// The result is always verified against the predicate, whatever options.Verify is.
+func FindOne[T any](items []T, predicate func(T) bool, options *easyq.SearchOptions) (*Result[T], error) {
+	return FindOneContext(context.Background(), items, predicate, options)
+}
+
+// FindOneContext is like FindOne but honours the deadline and cancellation of ctx.
+func FindOneContext[T any](ctx context.Context, items []T, predicate func(T) bool, options *easyq.SearchOptions) (*Result[T], error) {
+	opts := oneOptions(options)
+
+	results, err := FindContext(ctx, items, predicate, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, easyq.ErrNoMatches
+	}
+
+	return &results[0], nil
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"errors"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+type user struct {
+	name string
+	age  int
+}
+
+func TestFind(t *testing.T) {
+	users := []user{{"Alice", 34}, {"Bob", 17}, {"Carol", 52}, {"Dave", 29}}
+
+	results, report, err := FindWithReport(users, func(u user) bool { return u.age > 40 }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 {
+		t.Fatal("no results")
+	}
+	for _, result := range results {
+		if result.Item != users[result.Index] || result.Item.age <= 40 {
+			t.Errorf("result %+v is not a matching element", result)
+		}
+	}
+	if !report.Verified || report.Qubits != 2 {
+		t.Errorf("report = %+v, want a verified search over 2 qubits", report)
+	}
+
+	one, err := FindOne(users, func(u user) bool { return u.name == "Bob" }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if one.Index != 1 || one.Item.name != "Bob" {
+		t.Errorf("FindOne() = %+v, want Bob at index 1", one)
+	}
+}
+
+func TestFindNoMatches(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	negative := func(x int) bool { return x < 0 }
+
+	tests := []struct {
+		name     string
+		strategy easyq.IterationStrategy
+	}{
+		{"optimal", easyq.Optimal},
+		{"exponential", easyq.Exponential},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.IterationStrategy = tt.strategy
+
+			results, report, err := FindWithReport(items, negative, &opts)
+			if !errors.Is(err, easyq.ErrNoMatches) {
+				t.Fatalf("error = %v, want ErrNoMatches", err)
+			}
+			if len(results) != 0 {
+				t.Errorf("got %d results, want none", len(results))
+			}
+			if report == nil || report.IterationStrategy != tt.strategy {
+				t.Errorf("report = %+v, want one for strategy %v", report, tt.strategy)
+			}
+		})
+	}
+
+	if _, err := FindOne(items, negative, nil); !errors.Is(err, easyq.ErrNoMatches) {
+		t.Errorf("FindOne() error = %v, want ErrNoMatches", err)
+	}
+	if _, err := Find(items, nil, nil); err == nil {
+		t.Error("Find with a nil predicate succeeded, want an error")
+	}
+}
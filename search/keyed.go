@@ -0,0 +1,185 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// SearchMap performs a quantum search over the entries of a map. Each result
+// holds the value of a matching entry as its Item and the entry's key as its
+// Key. Entries are searched in key order, which is the order of the result
+// indices. Keys of an ordered kind (integers, floats and strings) are sorted
+// by value; other keys are sorted by their fmt.Sprint form, with ties broken
+// by their type and Go syntax, which keeps the order stable between calls. Options may be nil,
+// in which case default options are used.
+//
+// Example:
+//
+//	stock := map[string]int{"apple": 3, "banana": 0, "cherry": 12}
+//	results, err := search.SearchMap(stock, func(name string, count int) bool { return count == 0 }, nil)
+//	fmt.Println(results[0].Key) // banana
+func SearchMap[K comparable, V any](m map[K]V, predicate func(K, V) bool, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	return SearchMapContext(context.Background(), m, predicate, options)
+}
+
+// SearchMapContext is like SearchMap but honours the deadline and cancellation of ctx.
+func SearchMapContext[K comparable, V any](ctx context.Context, m map[K]V, predicate func(K, V) bool, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	if predicate == nil {
+		return nil, errors.New("predicate cannot be nil")
+	}
+
+	// Sort the keys so that indices are stable between calls
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		values[i] = m[key]
+	}
+
+	sp := searchPredicate{
+		matches: func(i int) bool {
+			return predicate(keys[i], values[i])
+		},
+	}
+	return searchKeyed(ctx, values, sp, func(i int) interface{} { return keys[i] }, options)
+}
+
+// sortKeys sorts map keys by value if they are of an ordered kind, and by
+// their printed form otherwise
+func sortKeys[K comparable](keys []K) {
+	if len(keys) == 0 {
+		return
+	}
+
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		})
+	case reflect.Float32, reflect.Float64:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		})
+	case reflect.String:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		})
+	default:
+		printed := make(map[K][2]string, len(keys))
+		for _, key := range keys {
+			// Distinct keys can print alike, such as pointers to equal values
+			detail := fmt.Sprintf("%T %#v", key, key)
+			if v := reflect.ValueOf(key); v.Kind() == reflect.Pointer {
+				detail += fmt.Sprintf(" %#x", v.Pointer())
+			}
+			printed[key] = [2]string{fmt.Sprint(key), detail}
+		}
+		slices.SortFunc(keys, func(a, b K) int {
+			pa, pb := printed[a], printed[b]
+			return cmp.Or(cmp.Compare(pa[0], pb[0]), cmp.Compare(pa[1], pb[1]))
+		})
+	}
+}
+
+// RowScanner is the part of *sql.Rows used by SearchRows, which lets rows from
+// other sources be searched too.
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// SearchRows performs a quantum search over database rows, such as the
+// *sql.Rows returned by a query. scan converts the current row into a value of
+// type T, typically by calling rows.Scan. Each result holds the scanned value
+// as its Item and the 1-based row number as its Key. The rows are read to the
+// end but not closed. Options may be nil, in which case default options are used.
+//
+// Example:
+//
+//	rows, err := db.QueryContext(ctx, "SELECT id, name, balance FROM accounts")
+//	defer rows.Close()
+//	results, err := search.SearchRowsContext(ctx, rows, func(rows search.RowScanner) (Account, error) {
+//		var a Account
+//		err := rows.Scan(&a.ID, &a.Name, &a.Balance)
+//		return a, err
+//	}, func(a Account) bool { return a.Balance < 0 }, nil)
+func SearchRows[T any](rows RowScanner, scan func(RowScanner) (T, error), predicate func(T) bool, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	return SearchRowsContext(context.Background(), rows, scan, predicate, options)
+}
+
+// SearchRowsContext is like SearchRows but honours the deadline and cancellation of ctx.
+func SearchRowsContext[T any](ctx context.Context, rows RowScanner, scan func(RowScanner) (T, error), predicate func(T) bool, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	if rows == nil {
+		return nil, errors.New("rows cannot be nil")
+	}
+	if scan == nil {
+		return nil, errors.New("scan cannot be nil")
+	}
+	if predicate == nil {
+		return nil, errors.New("predicate cannot be nil")
+	}
+
+	var items []T
+	for rows.Next() {
+		if len(items)%predicateCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		item, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(items)+1, err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sp := searchPredicate{
+		matches: func(i int) bool {
+			return predicate(items[i])
+		},
+	}
+	return searchKeyed(ctx, items, sp, func(i int) interface{} { return i + 1 }, options)
+}
+
+// searchKeyed runs a quantum search over the elements of items, which must be
+// a slice, and sets the Key of each result to key(result.Index). The Item of
+// each result is the original element rather than the copy returned by the
+// backend.
+func searchKeyed(ctx context.Context, items interface{}, sp searchPredicate, key func(i int) interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	itemsValue := reflect.ValueOf(items)
+	size := itemsValue.Len()
+
+	results, _, err := search(ctx, items, size, itemsValue.Type().Elem(), sp, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		index := results[i].Index
+		if index < 0 || index >= size {
+			return nil, fmt.Errorf("result index %d out of range [0, %d)", index, size)
+		}
+		results[i].Item = itemsValue.Index(index).Interface()
+		results[i].Key = key(index)
+	}
+
+	return results, nil
+}
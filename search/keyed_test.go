@@ -0,0 +1,112 @@
+package search
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSearchMap(t *testing.T) {
+	stock := map[string]int{"cherry": 12, "apple": 3, "banana": 0}
+	results, err := SearchMap(stock, func(name string, count int) bool { return count == 0 }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Key != "banana" || results[0].Item != 0 || results[0].Index != 1 {
+		t.Errorf("SearchMap() = %+v, want banana at index 1", results)
+	}
+
+	// Integer keys are sorted by value, not by their printed form
+	squares := map[int]int{10: 100, -5: 25, 2: 4}
+	results, err = SearchMap(squares, func(x, square int) bool { return x == 2 }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Key != 2 || results[0].Index != 1 {
+		t.Errorf("SearchMap() = %+v, want key 2 at index 1", results)
+	}
+}
+
+type point struct {
+	x, y int
+}
+
+func TestSearchMapComparableKeys(t *testing.T) {
+	grid := map[point]string{{1, 2}: "a", {0, 5}: "b", {3, 0}: "c", {1, 1}: "d"}
+	results, err := SearchMap(grid, func(p point, label string) bool { return p.x == 3 }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Key != (point{3, 0}) || results[0].Item != "c" {
+		t.Errorf("SearchMap() = %+v, want key {3 0}", results)
+	}
+
+	// The order is stable, even for keys that print alike
+	a, b := 7, 7
+	keys := []interface{}{point{1, 2}, &a, "x", 1.5, &b, point{0, 5}, 3}
+	want := slices.Clone(keys)
+	sortKeys(want)
+
+	reversed := slices.Clone(keys)
+	slices.Reverse(reversed)
+	rotated := append(slices.Clone(keys[3:]), keys[:3]...)
+	for _, order := range [][]interface{}{reversed, rotated} {
+		sortKeys(order)
+		if !slices.Equal(order, want) {
+			t.Errorf("sortKeys order %v differs from %v", order, want)
+		}
+	}
+}
+
+// fakeRows is a RowScanner over values, each scanned into a single destination
+type fakeRows struct {
+	values []int
+	next   int
+	err    error
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	if r.values[r.next-1] < 0 {
+		return errors.New("negative value")
+	}
+	*dest[0].(*int) = r.values[r.next-1]
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return r.err
+}
+
+func scanInt(rows RowScanner) (int, error) {
+	var value int
+	err := rows.Scan(&value)
+	return value, err
+}
+
+func TestSearchRows(t *testing.T) {
+	rows := &fakeRows{values: []int{40, 10, 30, 20}}
+	results, err := SearchRows(rows, scanInt, func(x int) bool { return x == 30 }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Key != 3 || results[0].Item != 30 {
+		t.Errorf("SearchRows() = %+v, want row 3", results)
+	}
+
+	rows = &fakeRows{values: []int{1, -1, 2}}
+	if _, err := SearchRows(rows, scanInt, func(int) bool { return true }, nil); err == nil || !strings.Contains(err.Error(), "row 2") {
+		t.Errorf("SearchRows() error = %v, want a scan error for row 2", err)
+	}
+
+	errRows := errors.New("connection lost")
+	rows = &fakeRows{values: []int{1}, err: errRows}
+	if _, err := SearchRows(rows, scanInt, func(int) bool { return true }, nil); !errors.Is(err, errRows) {
+		t.Errorf("SearchRows() error = %v, want the rows error", err)
+	}
+}
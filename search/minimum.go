@@ -0,0 +1,180 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// Extremum is the result of Minimum or Maximum, and an element of the result of TopK
+type Extremum[T any] struct {
+	// Item is the smallest (or largest) element found
+	Item T
+
+	// Index is the index of Item in the items searched
+	Index int
+
+	// OracleCalls is the total number of oracle applications over all threshold searches
+	OracleCalls int
+
+	// Searches is the number of threshold searches that ran
+	Searches int
+}
+
+// Minimum finds the smallest of items under less using the algorithm of Dürr
+// and Høyer. Starting from a random element as the threshold, it repeatedly
+// searches for an element less than the threshold and makes it the new
+// threshold, until a search finds nothing. This takes O(sqrt(N)) oracle calls
+// in expectation.
+//
+// Each threshold search uses the Exponential iteration strategy, whatever
+// options.IterationStrategy is, so the result is the minimum with probability
+// options.NoMatchConfidence. Options may be nil, in which case default options
+// are used. If several elements are smallest, any of them may be returned.
+//
+// Example:
+//
+//	cheapest, err := search.Minimum(offers, func(a, b Offer) bool { return a.Price < b.Price }, nil)
+//	fmt.Printf("offer %d costs %v (%d oracle calls)\n", cheapest.Index, cheapest.Item.Price, cheapest.OracleCalls)
+func Minimum[T any](items []T, less func(a, b T) bool, options *easyq.SearchOptions) (*Extremum[T], error) {
+	return MinimumContext(context.Background(), items, less, options)
+}
+
+// MinimumContext is like Minimum but honours the deadline and cancellation of ctx.
+func MinimumContext[T any](ctx context.Context, items []T, less func(a, b T) bool, options *easyq.SearchOptions) (*Extremum[T], error) {
+	if len(items) == 0 {
+		return nil, errors.New("items cannot be empty")
+	}
+	if less == nil {
+		return nil, errors.New("less cannot be nil")
+	}
+
+	// Use default options if none provided
+	opts := DefaultOptions()
+	if options != nil {
+		opts = *options
+	}
+
+	return minimum(ctx, items, less, nil, opts)
+}
+
+// minimum finds the smallest of the items whose index is not excluded.
+// excluded may be nil, and must leave at least one item.
+func minimum[T any](ctx context.Context, items []T, less func(a, b T) bool, excluded []bool, opts easyq.SearchOptions) (*Extremum[T], error) {
+	opts.IterationStrategy = easyq.Exponential
+	included := func(i int) bool {
+		return excluded == nil || !excluded[i]
+	}
+
+	// Start from a random included item
+	remaining := 0
+	for i := range items {
+		if included(i) {
+			remaining++
+		}
+	}
+	threshold, skip := -1, rand.IntN(remaining)
+	for i := range items {
+		if included(i) {
+			if skip == 0 {
+				threshold = i
+				break
+			}
+			skip--
+		}
+	}
+
+	result := &Extremum[T]{}
+	for {
+		below := func(i int) bool {
+			return included(i) && less(items[i], items[threshold])
+		}
+
+		found, report, err := search(ctx, items, len(items), reflect.TypeFor[T](), searchPredicate{matches: below}, &opts)
+		result.Searches++
+		if report != nil {
+			result.OracleCalls += report.OracleCalls
+		}
+		if errors.Is(err, easyq.ErrNoMatches) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		index := found[0].Index
+		if index < 0 || index >= len(items) {
+			return nil, fmt.Errorf("result index %d out of range [0, %d)", index, len(items))
+		}
+		threshold = index
+	}
+
+	result.Item = items[threshold]
+	result.Index = threshold
+	return result, nil
+}
+
+// Maximum finds the largest of items under less. It is Minimum with the
+// order reversed.
+func Maximum[T any](items []T, less func(a, b T) bool, options *easyq.SearchOptions) (*Extremum[T], error) {
+	return MaximumContext(context.Background(), items, less, options)
+}
+
+// MaximumContext is like Maximum but honours the deadline and cancellation of ctx.
+func MaximumContext[T any](ctx context.Context, items []T, less func(a, b T) bool, options *easyq.SearchOptions) (*Extremum[T], error) {
+	if less == nil {
+		return nil, errors.New("less cannot be nil")
+	}
+
+	return MinimumContext(ctx, items, func(a, b T) bool { return less(b, a) }, options)
+}
+
+// TopK finds the k items with the highest score, best first, by repeating
+// Maximum with the items already found excluded from the oracle. Any
+// combination of criteria can be ranked by folding it into score. Each result
+// holds the oracle calls spent finding it. Options may be nil, in which case
+// default options are used.
+//
+// Example:
+//
+//	best, err := search.TopK(hotels, 3, func(h Hotel) float64 { return h.Rating - h.Price/100 }, nil)
+func TopK[T any](items []T, k int, score func(T) float64, options *easyq.SearchOptions) ([]Extremum[T], error) {
+	return TopKContext(context.Background(), items, k, score, options)
+}
+
+// TopKContext is like TopK but honours the deadline and cancellation of ctx.
+func TopKContext[T any](ctx context.Context, items []T, k int, score func(T) float64, options *easyq.SearchOptions) ([]Extremum[T], error) {
+	if k <= 0 || k > len(items) {
+		return nil, fmt.Errorf("k must be between 1 and %d", len(items))
+	}
+	if score == nil {
+		return nil, errors.New("score cannot be nil")
+	}
+
+	// Use default options if none provided
+	opts := DefaultOptions()
+	if options != nil {
+		opts = *options
+	}
+
+	better := func(a, b T) bool {
+		return score(a) > score(b)
+	}
+
+	excluded := make([]bool, len(items))
+	results := make([]Extremum[T], 0, k)
+	for len(results) < k {
+		best, err := minimum(ctx, items, better, excluded, opts)
+		if err != nil {
+			return nil, err
+		}
+		excluded[best.Index] = true
+		results = append(results, *best)
+	}
+
+	return results, nil
+}
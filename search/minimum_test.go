@@ -0,0 +1,116 @@
+package search
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// exactOptions makes a missed threshold search, and so a wrong extremum, negligibly unlikely
+func exactOptions() *easyq.SearchOptions {
+	opts := DefaultOptions()
+	opts.NoMatchConfidence = 1 - 1e-9
+	return &opts
+}
+
+func TestMinimumMaximum(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for round := range 20 {
+		items := make([]int, 1+rng.IntN(64))
+		for i := range items {
+			items[i] = rng.IntN(100) - 50
+		}
+
+		minimum, err := Minimum(items, less, exactOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := slices.Min(items); minimum.Item != want || items[minimum.Index] != want {
+			t.Errorf("round %d: Minimum(%v) = %+v, want %d", round, items, minimum, want)
+		}
+		if minimum.Searches == 0 {
+			t.Errorf("round %d: Minimum ran no threshold searches", round)
+		}
+
+		maximum, err := Maximum(items, less, exactOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := slices.Max(items); maximum.Item != want || items[maximum.Index] != want {
+			t.Errorf("round %d: Maximum(%v) = %+v, want %d", round, items, maximum, want)
+		}
+	}
+}
+
+func TestMinimumDuplicates(t *testing.T) {
+	items := []int{5, 1, 3, 1, 7, 1, 9, 1}
+	for range 10 {
+		minimum, err := Minimum(items, func(a, b int) bool { return a < b }, exactOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if minimum.Item != 1 || items[minimum.Index] != 1 {
+			t.Fatalf("Minimum() = %+v, want one of the items equal to 1", minimum)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	items := make([]float64, 40)
+	for i := range items {
+		items[i] = float64(rng.IntN(20))
+	}
+	score := func(x float64) float64 { return x }
+
+	for _, k := range []int{1, 5, len(items)} {
+		results, err := TopK(items, k, score, exactOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := slices.SortedFunc(slices.Values(items), func(a, b float64) int { return cmp.Compare(b, a) })[:k]
+		seen := make(map[int]bool)
+		for i, result := range results {
+			if result.Item != want[i] || items[result.Index] != result.Item {
+				t.Errorf("TopK(%d) result %d = %+v, want item %v", k, i, result, want[i])
+			}
+			if seen[result.Index] {
+				t.Errorf("TopK(%d) returned index %d twice", k, result.Index)
+			}
+			seen[result.Index] = true
+		}
+	}
+}
+
+func TestExtremumInvalidInputs(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	score := func(x int) float64 { return float64(x) }
+	items := []int{3, 1, 2}
+
+	if _, err := Minimum([]int{}, less, nil); err == nil {
+		t.Error("Minimum on no items succeeded, want an error")
+	}
+	if _, err := Maximum([]int{}, less, nil); err == nil {
+		t.Error("Maximum on no items succeeded, want an error")
+	}
+	if _, err := Minimum(items, nil, nil); err == nil {
+		t.Error("Minimum with a nil less succeeded, want an error")
+	}
+	for _, k := range []int{-1, 0, len(items) + 1} {
+		if _, err := TopK(items, k, score, nil); err == nil {
+			t.Errorf("TopK with k = %d succeeded, want an error", k)
+		}
+	}
+	if _, err := TopK([]int{}, 1, score, nil); err == nil {
+		t.Error("TopK on no items succeeded, want an error")
+	}
+	if _, err := TopK(items, 1, nil, nil); err == nil {
+		t.Error("TopK with a nil score succeeded, want an error")
+	}
+}
@@ -0,0 +1,167 @@
+// Package oracle compiles search predicates into reversible phase-oracle circuits.
+//
+// On quantum hardware, the oracle of Grover's algorithm cannot be a lookup
+// table: it has to be a circuit that flips the phase of every basis state of
+// the search register that satisfies the predicate. Compile builds such a
+// circuit from simple integer predicates (equality, ranges and bitmask tests,
+// combined with And, Or and Not), CompileLookup applies such a predicate to
+// the values stored at each index, and CompileIndices builds one that marks a
+// given set of indices.
+//
+// Circuits use only X and Z gates with any number of controls. Work qubits
+// (ancillas) are allocated after the search register, reused where possible,
+// and always returned to |0⟩.
+package oracle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GateOp identifies the operation of a Gate
+type GateOp string
+
+const (
+	// GateX flips the target qubit when all controls are |1⟩
+	GateX GateOp = "X"
+
+	// GateZ flips the phase when all controls and the target are |1⟩
+	GateZ GateOp = "Z"
+)
+
+// Gate is a multi-controlled X or Z gate
+type Gate struct {
+	// Op is the gate operation
+	Op GateOp
+
+	// Controls are the control qubits. The gate applies when all are |1⟩.
+	Controls []int `json:",omitempty"`
+
+	// Target is the qubit the gate acts on
+	Target int
+}
+
+// Circuit is a reversible phase-oracle circuit.
+//
+// Qubits 0 to Qubits-1 hold the search register, with qubit 0 as its least
+// significant bit. Qubits Qubits to Qubits+Ancillas-1 are ancillas, which
+// start and end in |0⟩.
+type Circuit struct {
+	// Qubits is the width of the search register
+	Qubits int
+
+	// Ancillas is the number of ancilla qubits used by the circuit
+	Ancillas int
+
+	// Gates are the gates of the circuit, in order of application
+	Gates []Gate
+}
+
+// Stats summarizes the resources used by a circuit
+type Stats struct {
+	// Qubits is the width of the search register
+	Qubits int
+
+	// Ancillas is the number of ancilla qubits
+	Ancillas int
+
+	// TotalQubits is the number of qubits the circuit runs on
+	TotalQubits int
+
+	// Gates is the total number of gates
+	Gates int
+
+	// SingleQubitGates is the number of gates without controls
+	SingleQubitGates int
+
+	// ControlledGates is the number of gates with one control (CNOT and CZ)
+	ControlledGates int
+
+	// ToffoliGates is the number of gates with two controls
+	ToffoliGates int
+
+	// MultiControlledGates is the number of gates with more than two controls,
+	// which hardware must decompose further
+	MultiControlledGates int
+
+	// MaxControls is the largest number of controls on a single gate
+	MaxControls int
+}
+
+// ErrNotRestored is returned by Marks when a circuit changes the register or
+// does not return an ancilla to |0⟩
+var ErrNotRestored = errors.New("oracle: circuit does not restore its qubits")
+
+// Stats returns the resources used by the circuit.
+func (c *Circuit) Stats() Stats {
+	stats := Stats{
+		Qubits:      c.Qubits,
+		Ancillas:    c.Ancillas,
+		TotalQubits: c.Qubits + c.Ancillas,
+		Gates:       len(c.Gates),
+	}
+
+	for _, g := range c.Gates {
+		switch len(g.Controls) {
+		case 0:
+			stats.SingleQubitGates++
+		case 1:
+			stats.ControlledGates++
+		case 2:
+			stats.ToffoliGates++
+		default:
+			stats.MultiControlledGates++
+		}
+		stats.MaxControls = max(stats.MaxControls, len(g.Controls))
+	}
+
+	return stats
+}
+
+// String returns a summary of the circuit
+func (c *Circuit) String() string {
+	s := c.Stats()
+	return fmt.Sprintf("oracle circuit: %d qubits (%d register, %d ancilla), %d gates (max %d controls)",
+		s.TotalQubits, s.Qubits, s.Ancillas, s.Gates, s.MaxControls)
+}
+
+// Marks reports whether the circuit flips the phase of register basis state x.
+// It runs the circuit classically, which is possible because it only contains
+// X and Z gates, and fails if the register or an ancilla is not restored.
+func (c *Circuit) Marks(x uint64) (bool, error) {
+	total := c.Qubits + c.Ancillas
+	if total > 64 {
+		return false, fmt.Errorf("oracle: cannot evaluate a circuit on %d qubits", total)
+	}
+	if c.Qubits < 64 && x>>c.Qubits != 0 {
+		return false, fmt.Errorf("oracle: %d does not fit in a %d-qubit register", x, c.Qubits)
+	}
+
+	state := x
+	flipped := false
+	for _, g := range c.Gates {
+		var controls uint64
+		for _, q := range g.Controls {
+			controls |= 1 << q
+		}
+		if state&controls != controls {
+			continue
+		}
+
+		switch g.Op {
+		case GateX:
+			state ^= 1 << g.Target
+		case GateZ:
+			if state&(1<<g.Target) != 0 {
+				flipped = !flipped
+			}
+		default:
+			return false, fmt.Errorf("oracle: unknown gate %q", g.Op)
+		}
+	}
+
+	if state != x {
+		return false, ErrNotRestored
+	}
+	return flipped, nil
+}
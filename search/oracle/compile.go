@@ -0,0 +1,594 @@
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/Henrikarba/easyq-go/search/expr"
+)
+
+// MaxQubits is the widest search register the compiler supports
+const MaxQubits = 62
+
+// ErrUnsupported is returned when a predicate cannot be compiled to a circuit
+var ErrUnsupported = errors.New("oracle: predicate cannot be compiled to a circuit")
+
+// Width returns the number of register qubits needed to index size items.
+func Width(size int) int {
+	if size <= 2 {
+		return 1
+	}
+	return bits.Len(uint(size - 1))
+}
+
+// Compile compiles a predicate over unsigned integers of the given bit width
+// into a phase oracle that marks every register value satisfying it.
+//
+// The predicate must apply to the item itself (see expr.Item) and may only use
+// integer comparisons, Between, Masked and HasBits, combined with And, Or and
+// Not. Other predicates fail with ErrUnsupported.
+//
+// Example:
+//
+//	// Mark the even numbers between 10 and 40 in a 6-qubit register
+//	circuit, err := oracle.Compile(expr.And(
+//		expr.Item().Between(10, 40),
+//		expr.Item().Masked(1, 0),
+//	), 6)
+//	fmt.Println(circuit.Stats().Gates)
+func Compile(p expr.Predicate, width int) (*Circuit, error) {
+	if width < 1 || width > MaxQubits {
+		return nil, fmt.Errorf("oracle: register width %d out of range [1, %d]", width, MaxQubits)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &compiler{width: width}
+	n, err := c.node(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.phaseOracle(n), nil
+}
+
+// CompileIndices compiles a phase oracle that marks exactly the given register
+// values. Runs of consecutive indices are compiled as range comparisons, so
+// the circuit grows with the number of runs rather than the number of indices.
+func CompileIndices(indices []int, width int) (*Circuit, error) {
+	if width < 1 || width > MaxQubits {
+		return nil, fmt.Errorf("oracle: register width %d out of range [1, %d]", width, MaxQubits)
+	}
+
+	sorted := slices.Clone(indices)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	c := &compiler{width: width}
+	n := &node{kind: cubesNode}
+	for i := 0; i < len(sorted); {
+		first := sorted[i]
+		if first < 0 || uint64(first) > c.maxValue() {
+			return nil, fmt.Errorf("oracle: index %d does not fit in a %d-qubit register", first, width)
+		}
+
+		// Extend the run of consecutive indices
+		last := first
+		for i++; i < len(sorted) && sorted[i] == last+1; i++ {
+			last++
+		}
+		if uint64(last) > c.maxValue() {
+			return nil, fmt.Errorf("oracle: index %d does not fit in a %d-qubit register", last, width)
+		}
+
+		n.cubes = append(n.cubes, c.between(uint64(first), uint64(last))...)
+	}
+
+	return c.phaseOracle(n), nil
+}
+
+// CompileLookup compiles a phase oracle over a register of indices that marks
+// index i when values[i] satisfies the predicate. A lookup circuit writes the
+// value of each index into ancillas, the circuit Compile builds for the
+// predicate tests them, and the lookup is undone. Indices past the end of
+// values are never marked.
+//
+// The predicate must be one Compile supports, and every value must fit in
+// MaxQubits bits. If no value the padding indices could load fails the
+// predicate, CompileLookup fails with ErrUnsupported; CompileIndices can mark
+// the matching indices instead.
+func CompileLookup(p expr.Predicate, values []uint64, width int) (*Circuit, error) {
+	if width < 1 || width > MaxQubits {
+		return nil, fmt.Errorf("oracle: register width %d out of range [1, %d]", width, MaxQubits)
+	}
+	if uint64(len(values)) > 1<<width {
+		return nil, fmt.Errorf("oracle: %d values do not fit in a %d-qubit register", len(values), width)
+	}
+
+	var all uint64
+	for _, v := range values {
+		all |= v
+	}
+	valueWidth := max(bits.Len64(all), 1)
+	if valueWidth > MaxQubits {
+		return nil, fmt.Errorf("oracle: values do not fit in %d qubits", MaxQubits)
+	}
+
+	test, err := Compile(p, valueWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Indices past the end of values load a value that fails the predicate
+	c := &compiler{width: width}
+	padded := uint64(len(values)) < 1<<width
+	var padding uint64
+	if padded {
+		matches, err := expr.Compile[uint64](p)
+		if err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(values, func(v uint64) bool { return !matches(v) })
+		switch {
+		case !matches(0):
+		case i >= 0:
+			padding = values[i]
+		default:
+			return nil, fmt.Errorf("%w: every value satisfies the predicate", ErrUnsupported)
+		}
+	}
+
+	// The value register follows the index register, and the ancillas of
+	// the test follow the value register
+	c.ancillas = valueWidth + test.Ancillas
+	load := func(cb cube, v uint64) {
+		c.applyCube(cb, func(controls []int) {
+			for b := 0; b < valueWidth; b++ {
+				if v&(1<<b) != 0 {
+					c.emit(GateX, controls, width+b)
+				}
+			}
+		})
+	}
+	for i, v := range values {
+		if v != 0 {
+			load(cube{care: c.maxValue(), value: uint64(i)}, v)
+		}
+	}
+	if padded && padding != 0 {
+		for _, cb := range c.between(uint64(len(values)), c.maxValue()) {
+			load(cb, padding)
+		}
+	}
+	loaded := len(c.gates)
+
+	for _, g := range test.Gates {
+		controls := make([]int, len(g.Controls))
+		for i, q := range g.Controls {
+			controls[i] = width + q
+		}
+		c.emit(g.Op, controls, width+g.Target)
+	}
+	c.uncompute(0, loaded)
+
+	return &Circuit{
+		Qubits:   width,
+		Ancillas: c.ancillas,
+		Gates:    cancelInverses(c.gates),
+	}, nil
+}
+
+// cube is the set of register values whose bits selected by care equal value
+type cube struct {
+	care  uint64
+	value uint64
+}
+
+// nodeKind is the kind of a node of a compiled predicate
+type nodeKind int
+
+const (
+	// cubesNode is true for values in an odd number of its cubes
+	cubesNode nodeKind = iota
+
+	// notNode negates its single argument
+	notNode
+
+	// andNode is true when all its arguments are
+	andNode
+)
+
+// node is a predicate reduced to cubes, Not and And
+type node struct {
+	kind  nodeKind
+	cubes []cube
+	args  []*node
+}
+
+// compiler accumulates the gates of a circuit and manages its ancillas
+type compiler struct {
+	width int
+	gates []Gate
+
+	// ancillas is the number of ancillas allocated so far, and free holds
+	// those that are back in |0⟩ and can be reused
+	ancillas int
+	free     []int
+}
+
+func (c *compiler) maxValue() uint64 {
+	return 1<<c.width - 1
+}
+
+// node reduces a validated predicate to cubes, Not and And
+func (c *compiler) node(p expr.Predicate) (*node, error) {
+	switch p.Op {
+	case expr.OpAnd:
+		n := &node{kind: andNode}
+		for _, arg := range p.Args {
+			argNode, err := c.node(arg)
+			if err != nil {
+				return nil, err
+			}
+			n.args = append(n.args, argNode)
+		}
+		return n, nil
+
+	case expr.OpOr:
+		// a ∨ b = ¬(¬a ∧ ¬b)
+		n := &node{kind: andNode}
+		for _, arg := range p.Args {
+			argNode, err := c.node(arg)
+			if err != nil {
+				return nil, err
+			}
+			n.args = append(n.args, not(argNode))
+		}
+		return not(n), nil
+
+	case expr.OpNot:
+		arg, err := c.node(p.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return not(arg), nil
+	}
+
+	if p.Field != "" {
+		return nil, fmt.Errorf("%w: field %q (only the item itself can be compared)", ErrUnsupported, p.Field)
+	}
+
+	switch p.Op {
+	case expr.OpEq, expr.OpNe, expr.OpLt, expr.OpLe, expr.OpGt, expr.OpGe:
+		v, err := integer(p.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		n := &node{kind: cubesNode}
+		switch p.Op {
+		case expr.OpEq, expr.OpNe:
+			if v >= 0 && uint64(v) <= c.maxValue() {
+				n.cubes = []cube{{care: c.maxValue(), value: uint64(v)}}
+			}
+		case expr.OpLt, expr.OpGe:
+			n.cubes = c.lessThan(v)
+		case expr.OpLe, expr.OpGt:
+			if v >= int64(c.maxValue()) {
+				n.cubes = []cube{{}}
+			} else {
+				n.cubes = c.lessThan(v + 1)
+			}
+		}
+
+		if p.Op == expr.OpNe || p.Op == expr.OpGe || p.Op == expr.OpGt {
+			return not(n), nil
+		}
+		return n, nil
+
+	case expr.OpBetween:
+		low, err := integer(p.Min)
+		if err != nil {
+			return nil, err
+		}
+		high, err := integer(p.Max)
+		if err != nil {
+			return nil, err
+		}
+
+		// Clip the range to the register values
+		n := &node{kind: cubesNode}
+		low = max(low, 0)
+		high = min(high, int64(c.maxValue()))
+		if low <= high {
+			n.cubes = c.between(uint64(low), uint64(high))
+		}
+		return n, nil
+
+	case expr.OpMasked:
+		v, err := integer(p.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		n := &node{kind: cubesNode}
+		if uint64(v)&^p.Mask == 0 && uint64(v)&^c.maxValue() == 0 {
+			n.cubes = []cube{{care: p.Mask & c.maxValue(), value: uint64(v)}}
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("%w: operation %s", ErrUnsupported, p.Op)
+}
+
+// lessThan returns cubes for the register values below v.
+// For each set bit k of v, the values with the bits of v above k and a clear
+// bit k are below v, and these sets are disjoint.
+func (c *compiler) lessThan(v int64) []cube {
+	switch {
+	case v <= 0:
+		return nil
+	case uint64(v) > c.maxValue():
+		return []cube{{}}
+	}
+
+	var cubes []cube
+	for k := c.width - 1; k >= 0; k-- {
+		bit := uint64(1) << k
+		if uint64(v)&bit == 0 {
+			continue
+		}
+		care := c.maxValue() &^ (bit - 1)
+		cubes = append(cubes, cube{care: care, value: uint64(v) & care &^ bit})
+	}
+	return cubes
+}
+
+// between returns cubes for the register values in [min, max], given that
+// min <= max <= maxValue. Since the values below min are a subset of the
+// values up to max, XOR-ing the two sets leaves exactly the range.
+func (c *compiler) between(min, max uint64) []cube {
+	cubes := c.lessThan(int64(min))
+	if max == c.maxValue() {
+		return append(cubes, cube{})
+	}
+	return append(cubes, c.lessThan(int64(max)+1)...)
+}
+
+// phaseOracle emits the phase oracle of n and returns the finished circuit
+func (c *compiler) phaseOracle(n *node) *Circuit {
+	if n.kind == cubesNode && !slices.Contains(n.cubes, cube{}) {
+		// Flip the phase of each cube directly, without an ancilla
+		for _, cb := range n.cubes {
+			c.applyCube(cb, func(controls []int) {
+				c.emit(GateZ, controls[:len(controls)-1], controls[len(controls)-1])
+			})
+		}
+	} else {
+		// Compute the predicate into an ancilla, kick back the phase and uncompute
+		out := c.alloc()
+		start := len(c.gates)
+		c.compute(n, out)
+		end := len(c.gates)
+		c.emit(GateZ, nil, out)
+		c.uncompute(start, end)
+		c.release(out)
+	}
+
+	return &Circuit{
+		Qubits:   c.width,
+		Ancillas: c.ancillas,
+		Gates:    cancelInverses(c.gates),
+	}
+}
+
+// compute XORs the value of n into target, which must not be used by n
+func (c *compiler) compute(n *node, target int) {
+	switch n.kind {
+	case cubesNode:
+		for _, cb := range n.cubes {
+			c.applyCube(cb, func(controls []int) {
+				c.emit(GateX, controls, target)
+			})
+		}
+
+	case notNode:
+		c.compute(n.args[0], target)
+		c.emit(GateX, nil, target)
+
+	case andNode:
+		// Arguments that are a single cube become controls of the final gate
+		// directly; the others are computed into ancillas first.
+		merged := cube{}
+		var computed []*node
+		for _, arg := range n.args {
+			if arg.kind == cubesNode && len(arg.cubes) == 1 {
+				cb := arg.cubes[0]
+				if (merged.value^cb.value)&merged.care&cb.care != 0 {
+					// Contradictory cubes: the conjunction is always false
+					return
+				}
+				merged = cube{care: merged.care | cb.care, value: merged.value | cb.value}
+				continue
+			}
+			computed = append(computed, arg)
+		}
+
+		start := len(c.gates)
+		ancillas := make([]int, len(computed))
+		for i, arg := range computed {
+			ancillas[i] = c.alloc()
+			c.compute(arg, ancillas[i])
+		}
+		end := len(c.gates)
+
+		c.applyCube(merged, func(controls []int) {
+			c.emit(GateX, append(controls, ancillas...), target)
+		})
+
+		c.uncompute(start, end)
+		for _, q := range ancillas {
+			c.release(q)
+		}
+	}
+}
+
+// uncompute appends the inverse of the gates emitted from start to end. Every
+// gate is its own inverse, so this is the same gates in reverse order.
+func (c *compiler) uncompute(start, end int) {
+	for i := end - 1; i >= start; i-- {
+		c.gates = append(c.gates, c.gates[i])
+	}
+}
+
+// applyCube calls apply with the register qubits of cb as controls, with X
+// gates around it on the qubits that must be |0⟩
+func (c *compiler) applyCube(cb cube, apply func(controls []int)) {
+	var controls, flips []int
+	for q := 0; q < c.width; q++ {
+		if cb.care&(1<<q) == 0 {
+			continue
+		}
+		controls = append(controls, q)
+		if cb.value&(1<<q) == 0 {
+			flips = append(flips, q)
+		}
+	}
+
+	for _, q := range flips {
+		c.emit(GateX, nil, q)
+	}
+	apply(controls)
+	for _, q := range flips {
+		c.emit(GateX, nil, q)
+	}
+}
+
+func (c *compiler) emit(op GateOp, controls []int, target int) {
+	c.gates = append(c.gates, Gate{Op: op, Controls: slices.Clone(controls), Target: target})
+}
+
+// alloc returns an ancilla in |0⟩, reusing a released one if possible
+func (c *compiler) alloc() int {
+	if n := len(c.free); n > 0 {
+		q := c.free[n-1]
+		c.free = c.free[:n-1]
+		return q
+	}
+	q := c.width + c.ancillas
+	c.ancillas++
+	return q
+}
+
+// release returns an ancilla that is back in |0⟩
+func (c *compiler) release(q int) {
+	c.free = append(c.free, q)
+}
+
+// cancelInverses removes pairs of identical gates that are adjacent on every
+// qubit they act on. Such pairs cancel, since every gate is its own inverse.
+func cancelInverses(gates []Gate) []Gate {
+	var out []Gate
+	removed := make(map[int]bool)
+	last := make(map[int][]int) // qubit -> indices in out of the gates acting on it, in order
+
+	for _, g := range gates {
+		qubits := append([]int{g.Target}, g.Controls...)
+
+		previous := -1
+		for i, q := range qubits {
+			stack := last[q]
+			if len(stack) == 0 {
+				previous = -1
+				break
+			}
+			if i == 0 {
+				previous = stack[len(stack)-1]
+			} else if stack[len(stack)-1] != previous {
+				previous = -1
+				break
+			}
+		}
+
+		if previous >= 0 && sameGate(out[previous], g) {
+			removed[previous] = true
+			for _, q := range qubits {
+				last[q] = last[q][:len(last[q])-1]
+			}
+			continue
+		}
+
+		for _, q := range qubits {
+			last[q] = append(last[q], len(out))
+		}
+		out = append(out, g)
+	}
+
+	result := make([]Gate, 0, len(out)-len(removed))
+	for i, g := range out {
+		if !removed[i] {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// sameGate reports whether a and b are the same gate, up to the order of controls
+func sameGate(a, b Gate) bool {
+	if a.Op != b.Op || a.Target != b.Target || len(a.Controls) != len(b.Controls) {
+		return false
+	}
+	ac, bc := slices.Clone(a.Controls), slices.Clone(b.Controls)
+	slices.Sort(ac)
+	slices.Sort(bc)
+	return slices.Equal(ac, bc)
+}
+
+func not(n *node) *node {
+	if n.kind == notNode {
+		return n.args[0]
+	}
+	return &node{kind: notNode, args: []*node{n}}
+}
+
+// integer converts a comparison value, which may have passed through JSON, to an integer.
+// Unsigned values above math.MaxInt64 saturate to it: like them, it is above
+// every register value, so they compile to the same cubes.
+func integer(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		if _, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return math.MaxInt64, nil
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), nil
+		}
+		if v == math.Trunc(v) && v >= 0 && v < 1<<64 {
+			return math.MaxInt64, nil
+		}
+	default:
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return rv.Int(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if rv.Uint() <= math.MaxInt64 {
+				return int64(rv.Uint()), nil
+			}
+			return math.MaxInt64, nil
+		case reflect.Float32:
+			return integer(rv.Float())
+		}
+	}
+	return 0, fmt.Errorf("%w: %v is not an integer", ErrUnsupported, value)
+}
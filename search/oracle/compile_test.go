@@ -0,0 +1,162 @@
+package oracle
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Henrikarba/easyq-go/search/expr"
+)
+
+// checkMarks checks that circuit marks exactly the register values for which want is true
+func checkMarks(t *testing.T, circuit *Circuit, want func(x uint64) bool) {
+	t.Helper()
+	for x := uint64(0); x < 1<<circuit.Qubits; x++ {
+		marked, err := circuit.Marks(x)
+		if err != nil {
+			t.Fatalf("Marks(%d): %v", x, err)
+		}
+		if marked != want(x) {
+			t.Fatalf("Marks(%d) = %v, want %v", x, marked, want(x))
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate expr.Predicate
+		width     int
+	}{
+		{"equal", expr.Item().Eq(5), 4},
+		{"equal out of range", expr.Item().Eq(40), 4},
+		{"not equal", expr.Item().Ne(0), 3},
+		{"less than", expr.Item().Lt(11), 5},
+		{"less or equal", expr.Item().Le(11), 5},
+		{"greater than", expr.Item().Gt(11), 5},
+		{"greater or equal", expr.Item().Ge(0), 5},
+		{"negative bound", expr.Item().Gt(-3), 4},
+		{"between", expr.Item().Between(10, 40), 6},
+		{"between clipped", expr.Item().Between(-5, 100), 5},
+		{"empty between", expr.Item().Between(9, 3), 4},
+		{"masked", expr.Item().Masked(0b101, 0b001), 5},
+		{"has bits", expr.Item().HasBits(0b110), 4},
+		{"unsigned above MaxInt64", expr.Item().Lt(uint64(math.MaxUint64)), 4},
+		{"and", expr.And(expr.Item().Between(10, 40), expr.Item().Masked(1, 0)), 6},
+		{"or", expr.Or(expr.Item().Lt(3), expr.Item().Eq(12), expr.Item().Gt(29)), 5},
+		{"not", expr.Not(expr.Item().Between(4, 9)), 4},
+		{"nested", expr.And(expr.Or(expr.Item().Lt(8), expr.Item().HasBits(16)), expr.Not(expr.Item().Eq(3))), 5},
+		{"contradiction", expr.And(expr.Item().Masked(1, 0), expr.Item().Masked(1, 1)), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, err := Compile(tt.predicate, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			match, err := expr.Compile[uint64](tt.predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkMarks(t, circuit, match)
+		})
+	}
+}
+
+func TestCompileUnsupported(t *testing.T) {
+	tests := []expr.Predicate{
+		expr.Field("Age").Gt(3),
+		expr.Item().Lt(2.5),
+		expr.Item().Eq("five"),
+		expr.Item().HasPrefix("x"),
+	}
+
+	for _, p := range tests {
+		if _, err := Compile(p, 4); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Compile(%v) error = %v, want ErrUnsupported", p, err)
+		}
+	}
+}
+
+func TestCompileIndices(t *testing.T) {
+	tests := []struct {
+		name    string
+		indices []int
+		width   int
+	}{
+		{"none", nil, 3},
+		{"single", []int{6}, 3},
+		{"run", []int{3, 4, 5, 6, 7, 8}, 4},
+		{"scattered", []int{0, 2, 9, 15}, 4},
+		{"duplicates", []int{5, 1, 5, 1}, 3},
+		{"all", []int{0, 1, 2, 3, 4, 5, 6, 7}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, err := CompileIndices(tt.indices, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkMarks(t, circuit, func(x uint64) bool {
+				return slices.Contains(tt.indices, int(x))
+			})
+		})
+	}
+
+	if _, err := CompileIndices([]int{8}, 3); err == nil {
+		t.Error("CompileIndices accepted an index that does not fit in the register")
+	}
+}
+
+func TestCompileLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate expr.Predicate
+		values    []uint64
+		width     int
+	}{
+		{"less than", expr.Item().Lt(5), []uint64{9, 3, 12, 1, 7}, 3},
+		{"equal", expr.Item().Eq(7), []uint64{7, 0, 7, 2}, 2},
+		{"padding matches zero", expr.Item().Lt(4), []uint64{1, 8, 2}, 2},
+		{"masked", expr.Item().Masked(1, 0), []uint64{4, 5, 6, 7, 8, 9}, 3},
+		{"range", expr.Item().Between(3, 20), []uint64{40, 3, 17, 8, 12, 99, 5}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, err := CompileLookup(tt.predicate, tt.values, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			match, err := expr.Compile[uint64](tt.predicate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkMarks(t, circuit, func(x uint64) bool {
+				return x < uint64(len(tt.values)) && match(tt.values[x])
+			})
+		})
+	}
+
+	// Padding indices cannot be left unmarked if every value matches
+	if _, err := CompileLookup(expr.Item().Ge(0), []uint64{1, 2, 3}, 2); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CompileLookup error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		size, want int
+	}{
+		{1, 1}, {2, 1}, {3, 2}, {4, 2}, {5, 3}, {1024, 10}, {1025, 11},
+	}
+
+	for _, tt := range tests {
+		if got := Width(tt.size); got != tt.want {
+			t.Errorf("Width(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
@@ -3,12 +3,17 @@
 package search
 
 import (
+	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"log"
 	"reflect"
+	"time"
 
 	easyq "github.com/Henrikarba/easyq-go"
-	"github.com/Henrikarba/easyq-go/bridge"
+	"github.com/Henrikarba/easyq-go/search/expr"
+	"github.com/Henrikarba/easyq-go/search/oracle"
 )
 
 // DefaultOptions returns a new Options with default values.
@@ -29,20 +34,133 @@ func DefaultOptions() easyq.SearchOptions {
 // It returns all items that match the predicate and their indices.
 // Options may be nil, in which case default options are used.
 //
+// The predicate is either a function with signature func(T) bool, where T is
+// the element type of items, or an expr.Predicate. Declarative predicates are
+// also sent to the backend in serialized form.
+//
+// Measurement is probabilistic, so results may include items that do not match.
+// By default, results of function predicates are checked against the predicate
+// and false positives are discarded; see SearchOptions.Verify.
+//
 // Example:
 //
 //	items := []string{"apple", "banana", "cherry", "date"}
 //	predicate := func(item string) bool { return len(item) > 5 }
 //	results, err := search.Search(items, predicate, nil)
+//
+//	// The same search with a declarative predicate
+//	results, err = search.Search(items, expr.Not(expr.Item().HasPrefix("d")), nil)
 func Search(items interface{}, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	return SearchContext(context.Background(), items, predicate, options)
+}
+
+// SearchContext is like Search but honours the deadline and cancellation of ctx.
+// If ctx is done before the search completes, ctx.Err() is returned, although
+// a native backend call that has already started keeps running until it returns.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	results, err := search.SearchContext(ctx, items, predicate, nil)
+func SearchContext(ctx context.Context, items interface{}, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, error) {
+	results, _, err := SearchWithReportContext(ctx, items, predicate, options)
+	return results, err
+}
+
+// SearchWithReport is like Search but also returns a report with statistics
+// about the search, such as the number of Grover iterations and attempts.
+// The report is also returned with ErrNoMatches.
+//
+// Example:
+//
+//	results, report, err := search.SearchWithReport(items, predicate, nil)
+//	if report != nil {
+//		fmt.Printf("%d iterations, %d/%d attempts, success probability %.2f\n",
+//			report.Iterations, report.Attempts, report.MaxAttempts, report.SuccessProbability)
+//	}
+func SearchWithReport(items interface{}, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, *easyq.SearchReport, error) {
+	return SearchWithReportContext(context.Background(), items, predicate, options)
+}
+
+// SearchWithReportContext is like SearchWithReport but honours the deadline
+// and cancellation of ctx. Cancellation does not interrupt a native backend
+// call that has already started.
+func SearchWithReportContext(ctx context.Context, items interface{}, predicate interface{}, options *easyq.SearchOptions) ([]easyq.SearchResult, *easyq.SearchReport, error) {
+	itemsValue, sp, err := newSearchPredicate(items, predicate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return search(ctx, items, itemsValue.Len(), itemsValue.Type().Elem(), sp, options)
+}
+
+// newSearchPredicate validates the items and predicate of the untyped API and
+// prepares the predicate for evaluation over the items
+func newSearchPredicate(items interface{}, predicate interface{}) (reflect.Value, searchPredicate, error) {
 	// Validate inputs
 	if err := validateInputs(items, predicate); err != nil {
-		return nil, err
+		return reflect.Value{}, searchPredicate{}, err
 	}
 
-	// Ensure we're initialized
-	if err := easyq.EnsureInitialized(); err != nil {
-		return nil, err
+	itemsValue := reflect.ValueOf(items)
+	itemType := itemsValue.Type().Elem()
+
+	var sp searchPredicate
+	if expression, ok := asExpression(predicate); ok {
+		match, err := expr.CompileType(expression, itemType)
+		if err != nil {
+			return reflect.Value{}, searchPredicate{}, err
+		}
+		sp.expression = &expression
+		sp.matches = func(i int) bool {
+			return match(itemsValue.Index(i))
+		}
+	} else {
+		predicateValue := reflect.ValueOf(predicate)
+		inType := predicateValue.Type().In(0)
+		if itemType.AssignableTo(inType) {
+			sp.matches = func(i int) bool {
+				return predicateValue.Call([]reflect.Value{itemsValue.Index(i)})[0].Bool()
+			}
+		} else {
+			// Items of interface type may hold values the predicate does not
+			// accept, and those never match
+			sp.matches = func(i int) bool {
+				item := itemsValue.Index(i).Elem()
+				if !item.IsValid() || !item.Type().AssignableTo(inType) {
+					return false
+				}
+				return predicateValue.Call([]reflect.Value{item})[0].Bool()
+			}
+		}
+	}
+
+	return itemsValue, sp, nil
+}
+
+// searchPredicate describes the predicate of a search
+type searchPredicate struct {
+	// matches reports whether the item at an index satisfies the predicate
+	matches func(i int) bool
+
+	// expression is the declarative form of the predicate, if it has one
+	expression *expr.Predicate
+}
+
+// search runs a quantum search on the size elements of items, whose elements
+// have type itemType, once the inputs have been validated
+func search(ctx context.Context, items interface{}, size int, itemType reflect.Type, sp searchPredicate, options *easyq.SearchOptions) ([]easyq.SearchResult, *easyq.SearchReport, error) {
+	start := time.Now()
+
+	// Resolve the session to run on, initializing if necessary
+	session, err := easyq.SessionFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := session.Client()
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Use default options if none provided
@@ -51,19 +169,138 @@ func Search(items interface{}, predicate interface{}, options *easyq.SearchOptio
 		opts = *options
 	}
 
-	// Prepare mapped predicate for serialization
-	mappedPredicate, err := convertPredicate(predicate, reflect.TypeOf(items).Elem())
+	// Serve repeated searches from the cache, if one is attached to ctx
+	cache := cacheFromContext(ctx)
+	var key [sha256.Size]byte
+	cacheable := false
+	if cache != nil {
+		key, cacheable = fingerprint(session.Config(), items, itemType, sp, opts)
+		if !cacheable {
+			cache.uncacheable()
+		} else if results, report, ok := cache.get(key); ok {
+			report.Cached = true
+			report.Duration = time.Since(start)
+			if len(results) == 0 {
+				return nil, report, easyq.ErrNoMatches
+			}
+			return results, report, nil
+		}
+	}
+
+	// Evaluate the predicate to build the oracle. Quantum hardware needs it
+	// as a circuit rather than a set of indices.
+	compileCircuit := session.Config().BackendType != easyq.Simulator
+	mappedPredicate, err := convertPredicate(ctx, items, size, itemType, sp, compileCircuit)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	circuit, _ := mappedPredicate["Oracle"].(*oracle.Circuit)
+	if circuit != nil && opts.EnableLogging {
+		log.Printf("easyq search: compiled %s", circuit)
 	}
 
 	// Perform the search through the bridge
-	rawResults, err := bridge.Search(items, mappedPredicate, opts)
-	if err != nil {
-		return nil, err
+	run := func() ([]interface{}, map[string]interface{}, error) {
+		return client.SearchWithReport(ctx, items, mappedPredicate, opts)
+	}
+	verify := opts.Verify == easyq.VerifyAlways || (opts.Verify == easyq.VerifyAuto && sp.expression == nil)
+	results, report, err := runSearch(ctx, start, opts, run, verify, size, sp.matches)
+	if circuit != nil && report != nil {
+		setOracleStats(report, circuit)
+	}
+	// Measurement can miss matches that exist, so a search that found nothing
+	// is only cached if the predicate marked no item when building the oracle
+	marked, _ := mappedPredicate["MarkedIndices"].([]int)
+	if cacheable && (err == nil || errors.Is(err, easyq.ErrNoMatches) && len(marked) == 0) {
+		cache.put(key, results, report)
+	}
+	return results, report, err
+}
+
+// setOracleStats records the resources of the compiled oracle in report
+func setOracleStats(report *easyq.SearchReport, circuit *oracle.Circuit) {
+	stats := circuit.Stats()
+	report.OracleQubits = stats.TotalQubits
+	report.OracleAncillas = stats.Ancillas
+	report.OracleGates = stats.Gates
+}
+
+// runSearch runs a search through run and converts its results and report.
+// If verify is set, results whose index is outside [0, size) or does not
+// satisfy matches are discarded, and the search is run again, up to
+// MaxAttempts times, while every result is discarded.
+func runSearch(ctx context.Context, start time.Time, opts easyq.SearchOptions, run func() ([]interface{}, map[string]interface{}, error), verify bool, size int, matches func(i int) bool) ([]easyq.SearchResult, *easyq.SearchReport, error) {
+	maxRuns := opts.MaxAttempts
+	if maxRuns <= 0 {
+		maxRuns = 5
+	}
+
+	var results []easyq.SearchResult
+	var report *easyq.SearchReport
+	candidates := 0
+	for {
+		rawResults, rawReport, err := run()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		results, err = convertResults(rawResults)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		runReport := convertReport(rawReport, opts)
+		if report != nil {
+			runReport.Attempts += report.Attempts
+			runReport.OracleCalls += report.OracleCalls
+			runReport.FalsePositives = report.FalsePositives
+			runReport.Retries = report.Retries + 1
+		}
+		report = runReport
+		if !verify {
+			break
+		}
+
+		// Discard the results that do not match the predicate, since
+		// measurement or hardware noise may return any item
+		report.Verified = true
+		candidates += len(results)
+		verified := results[:0]
+		for _, result := range results {
+			if result.Index >= 0 && result.Index < size && matches(result.Index) {
+				verified = append(verified, result)
+			}
+		}
+		rejected := len(results) - len(verified)
+		report.FalsePositives += rejected
+		results = verified
+
+		if len(results) > 0 || rejected == 0 || report.Retries+1 >= maxRuns {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		if opts.EnableLogging {
+			log.Printf("easyq search: all %d results were false positives, retrying", rejected)
+		}
+	}
+
+	if candidates > 0 {
+		report.FalsePositiveRate = float64(report.FalsePositives) / float64(candidates)
+	}
+	report.Duration = time.Since(start)
+
+	if len(results) == 0 {
+		return nil, report, easyq.ErrNoMatches
 	}
 
-	// Convert raw results to SearchResult objects
+	return results, report, nil
+}
+
+// convertResults converts the results returned by the bridge to SearchResult objects
+func convertResults(rawResults []interface{}) ([]easyq.SearchResult, error) {
 	results := make([]easyq.SearchResult, 0, len(rawResults))
 	for _, rawResult := range rawResults {
 		resultMap, ok := rawResult.(map[string]interface{})
@@ -91,31 +328,73 @@ func Search(items interface{}, predicate interface{}, options *easyq.SearchOptio
 		}
 		result.Item = itemValue
 
+		// Extract probability, if reported
+		if probability, ok := resultMap["Probability"].(float64); ok {
+			result.Probability = probability
+		}
+
 		results = append(results, result)
 	}
+	return results, nil
+}
 
-	if len(results) == 0 {
-		return nil, easyq.ErrNoMatches
+// convertReport converts the report document returned by the bridge.
+// Statistics the backend does not report are left as zero, except for the
+// strategies and attempts, which default to the requested options.
+func convertReport(rawReport map[string]interface{}, opts easyq.SearchOptions) *easyq.SearchReport {
+	report := &easyq.SearchReport{
+		IterationStrategy: opts.IterationStrategy,
+		SamplingStrategy:  opts.SamplingStrategy,
+		MaxAttempts:       opts.MaxAttempts,
+	}
+	if report.MaxAttempts <= 0 {
+		report.MaxAttempts = 5
 	}
 
-	return results, nil
+	if value, ok := rawReport["IterationStrategy"].(float64); ok {
+		report.IterationStrategy = easyq.IterationStrategy(value)
+	}
+	if value, ok := rawReport["SamplingStrategy"].(float64); ok {
+		report.SamplingStrategy = easyq.SamplingStrategy(value)
+	}
+	if value, ok := rawReport["EstimatedMatches"].(float64); ok {
+		report.EstimatedMatches = int(value)
+	}
+	if value, ok := rawReport["Iterations"].(float64); ok {
+		report.Iterations = int(value)
+	}
+	if value, ok := rawReport["Attempts"].(float64); ok {
+		report.Attempts = int(value)
+	}
+	if value, ok := rawReport["MaxAttempts"].(float64); ok {
+		report.MaxAttempts = int(value)
+	}
+	if value, ok := rawReport["OracleCalls"].(float64); ok {
+		report.OracleCalls = int(value)
+	}
+	if value, ok := rawReport["Qubits"].(float64); ok {
+		report.Qubits = int(value)
+	}
+	if value, ok := rawReport["SuccessProbability"].(float64); ok {
+		report.SuccessProbability = value
+	}
+
+	return report
 }
 
 // SearchOne performs a quantum search and returns the first matching item.
 // This is more efficient than Search when only one result is needed.
+// The result is always verified against the predicate, whatever options.Verify is.
 func SearchOne(items interface{}, predicate interface{}, options *easyq.SearchOptions) (*easyq.SearchResult, error) {
-	// Use default options if none provided
-	opts := DefaultOptions()
-	if options != nil {
-		opts = *options
-	}
+	return SearchOneContext(context.Background(), items, predicate, options)
+}
 
-	// Set to assume one match exists
-	opts.SamplingStrategy = easyq.AssumeOne
-	opts.MaxAttempts = 3 // Less attempts since we only need one match
+// SearchOneContext is like SearchOne but honours the deadline and cancellation of ctx.
+func SearchOneContext(ctx context.Context, items interface{}, predicate interface{}, options *easyq.SearchOptions) (*easyq.SearchResult, error) {
+	opts := oneOptions(options)
 
 	// Perform the search
-	results, err := Search(items, predicate, &opts)
+	results, err := SearchContext(ctx, items, predicate, &opts)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +406,26 @@ func SearchOne(items interface{}, predicate interface{}, options *easyq.SearchOp
 	return &results[0], nil
 }
 
-// validateInputs checks that the items and predicate are valid for quantum search
+// oneOptions returns the options used to search for a single match
+func oneOptions(options *easyq.SearchOptions) easyq.SearchOptions {
+	// Use default options if none provided
+	opts := DefaultOptions()
+	if options != nil {
+		opts = *options
+	}
+
+	// Set to assume one match exists
+	opts.SamplingStrategy = easyq.AssumeOne
+	opts.MaxAttempts = 3 // Less attempts since we only need one match
+
+	// A single result is never returned unverified
+	opts.Verify = easyq.VerifyAlways
+
+	return opts
+}
+
+// validateInputs checks that the items and predicate are valid for quantum search.
+// Only the untyped API needs it; Find is checked by the compiler.
 func validateInputs(items interface{}, predicate interface{}) error {
 	// Check if items is a slice or array
 	itemsType := reflect.TypeOf(items)
@@ -135,10 +433,15 @@ func validateInputs(items interface{}, predicate interface{}) error {
 		return errors.New("items must be a slice or array")
 	}
 
+	// Declarative predicates are checked when they are compiled
+	if _, ok := asExpression(predicate); ok {
+		return nil
+	}
+
 	// Check if predicate is a function
 	predicateType := reflect.TypeOf(predicate)
 	if predicateType == nil || predicateType.Kind() != reflect.Func {
-		return errors.New("predicate must be a function")
+		return errors.New("predicate must be a function or an expr.Predicate")
 	}
 
 	// Check if predicate has correct signature (func(T) bool)
@@ -146,8 +449,10 @@ func validateInputs(items interface{}, predicate interface{}) error {
 		return errors.New("predicate must have signature func(T) bool")
 	}
 
-	// Check if predicate input type matches items element type
-	if !predicateType.In(0).AssignableTo(itemsType.Elem()) {
+	// Check that items can be passed to the predicate. Items of interface type
+	// only need to be able to hold its input type, and are checked one by one.
+	inType, elemType := predicateType.In(0), itemsType.Elem()
+	if !elemType.AssignableTo(inType) && !(elemType.Kind() == reflect.Interface && inType.Implements(elemType)) {
 		return errors.New("predicate input type must match items element type")
 	}
 
@@ -159,20 +464,112 @@ func validateInputs(items interface{}, predicate interface{}) error {
 	return nil
 }
 
-// convertPredicate converts a Go function to a format that can be serialized
-// and understood by the bridge implementation
-func convertPredicate(predicate interface{}, itemType reflect.Type) (interface{}, error) {
-	// For simplicity, we'll create a placeholder representation here
-	// In a real implementation, you might serialize the function logic or use a different approach
+// predicateCheckInterval is how many items convertPredicate evaluates between
+// checks of the context
+const predicateCheckInterval = 1024
 
-	predicateValue := reflect.ValueOf(predicate)
-	predicateType := predicateValue.Type()
+// convertPredicate converts a predicate to a format that can be serialized
+// and understood by the bridge implementation.
+//
+// Go functions cannot cross the bridge, so the predicate is evaluated classically
+// over every item, and the indices of the matching items are sent as the
+// MarkedIndices of the oracle. The backend encodes exactly this set as the phase
+// oracle of Grover's algorithm, so results always reflect the predicate.
+// Declarative predicates are sent as well, in their JSON form, for backends
+// that build the oracle themselves.
+//
+// If compileCircuit is set, the predicate is also compiled into a reversible
+// phase-oracle circuit over the index register, sent as Oracle.
+func convertPredicate(ctx context.Context, items interface{}, size int, itemType reflect.Type, sp searchPredicate, compileCircuit bool) (map[string]interface{}, error) {
+	marked := make([]int, 0)
+	for i := 0; i < size; i++ {
+		if i%predicateCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if sp.matches(i) {
+			marked = append(marked, i)
+		}
+	}
 
 	// Create a representation of the predicate
-	return map[string]interface{}{
-		"Type":           "Function",
-		"InputType":      itemType.String(),
-		"ReturnType":     predicateType.Out(0).String(),
-		"SerializedFunc": fmt.Sprintf("%v", predicate),
-	}, nil
+	mapped := map[string]interface{}{
+		"Type":          "Function",
+		"InputType":     itemType.String(),
+		"ReturnType":    "bool",
+		"MarkedIndices": marked,
+	}
+	if sp.expression != nil {
+		mapped["Type"] = "Expression"
+		mapped["Expression"] = *sp.expression
+	}
+
+	if compileCircuit && size > 0 {
+		circuit, err := compileOracle(items, size, itemType, sp, marked)
+		if err != nil {
+			return nil, err
+		}
+		mapped["Oracle"] = circuit
+	}
+	return mapped, nil
+}
+
+// compileOracle compiles the phase oracle of a search over the index register.
+// A declarative predicate over integer items is compiled into the circuit
+// itself with oracle.CompileLookup. Other predicates, and those the compiler
+// does not support, are compiled from the marked indices with
+// oracle.CompileIndices.
+func compileOracle(items interface{}, size int, itemType reflect.Type, sp searchPredicate, marked []int) (*oracle.Circuit, error) {
+	width := oracle.Width(size)
+	if sp.expression != nil {
+		if values, ok := registerValues(items, size, itemType); ok {
+			circuit, err := oracle.CompileLookup(*sp.expression, values, width)
+			if err == nil {
+				return circuit, nil
+			}
+			if !errors.Is(err, oracle.ErrUnsupported) {
+				return nil, err
+			}
+		}
+	}
+	return oracle.CompileIndices(marked, width)
+}
+
+// registerValues returns the items as the unsigned values a circuit compares
+// them as, or false if they are not integers in [0, 2^oracle.MaxQubits)
+func registerValues(items interface{}, size int, itemType reflect.Type) ([]uint64, bool) {
+	itemsValue := reflect.ValueOf(items)
+	values := make([]uint64, size)
+	for i := range values {
+		switch itemType.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v := itemsValue.Index(i).Int()
+			if v < 0 {
+				return nil, false
+			}
+			values[i] = uint64(v)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			values[i] = itemsValue.Index(i).Uint()
+		default:
+			return nil, false
+		}
+		if values[i] >= 1<<oracle.MaxQubits {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// asExpression returns the declarative predicate held by predicate, if any
+func asExpression(predicate interface{}) (expr.Predicate, bool) {
+	switch p := predicate.(type) {
+	case expr.Predicate:
+		return p, true
+	case *expr.Predicate:
+		if p != nil {
+			return *p, true
+		}
+	}
+	return expr.Predicate{}, false
 }
@@ -0,0 +1,163 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/search/expr"
+)
+
+// fakeRuns returns a search run that returns the indices of each call in turn
+func fakeRuns(runs [][]int) (func() ([]interface{}, map[string]interface{}, error), *int) {
+	calls := 0
+	return func() ([]interface{}, map[string]interface{}, error) {
+		indices := runs[min(calls, len(runs)-1)]
+		calls++
+
+		results := make([]interface{}, len(indices))
+		for i, index := range indices {
+			results[i] = map[string]interface{}{"Index": float64(index), "Item": float64(index)}
+		}
+		return results, map[string]interface{}{"Attempts": 2.0, "OracleCalls": 10.0}, nil
+	}, &calls
+}
+
+func TestRunSearchVerify(t *testing.T) {
+	tests := []struct {
+		name               string
+		runs               [][]int
+		verify             bool
+		maxAttempts        int
+		wantIndices        []int
+		wantCalls          int
+		wantRetries        int
+		wantFalsePositives int
+		wantErr            error
+	}{
+		{"no verification", [][]int{{1, 2}}, false, 5, []int{1, 2}, 1, 0, 0, nil},
+		{"all match", [][]int{{2, 4}}, true, 5, []int{2, 4}, 1, 0, 0, nil},
+		{"false positive discarded", [][]int{{1, 4}}, true, 5, []int{4}, 1, 0, 1, nil},
+		{"out of range discarded", [][]int{{-1, 100, 6}}, true, 5, []int{6}, 1, 0, 2, nil},
+		{"retry after false positives", [][]int{{1}, {3, 5}, {2}}, true, 5, []int{2}, 3, 2, 3, nil},
+		{"retries exhausted", [][]int{{1}}, true, 3, nil, 3, 2, 3, easyq.ErrNoMatches},
+		{"nothing found is not retried", [][]int{{}}, true, 5, nil, 1, 0, 0, easyq.ErrNoMatches},
+	}
+
+	even := func(i int) bool { return i%2 == 0 }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, calls := fakeRuns(tt.runs)
+			opts := DefaultOptions()
+			opts.MaxAttempts = tt.maxAttempts
+
+			results, report, err := runSearch(context.Background(), time.Now(), opts, run, tt.verify, 10, even)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if len(results) != len(tt.wantIndices) {
+				t.Fatalf("got %d results, want indices %v", len(results), tt.wantIndices)
+			}
+			for i, result := range results {
+				if result.Index != tt.wantIndices[i] {
+					t.Errorf("result %d has index %d, want %d", i, result.Index, tt.wantIndices[i])
+				}
+			}
+
+			if *calls != tt.wantCalls {
+				t.Errorf("ran %d times, want %d", *calls, tt.wantCalls)
+			}
+			if report.Verified != tt.verify {
+				t.Errorf("Verified = %v, want %v", report.Verified, tt.verify)
+			}
+			if report.Retries != tt.wantRetries {
+				t.Errorf("Retries = %d, want %d", report.Retries, tt.wantRetries)
+			}
+			if report.FalsePositives != tt.wantFalsePositives {
+				t.Errorf("FalsePositives = %d, want %d", report.FalsePositives, tt.wantFalsePositives)
+			}
+			if report.Attempts != 2*tt.wantCalls || report.OracleCalls != 10*tt.wantCalls {
+				t.Errorf("Attempts = %d, OracleCalls = %d, want totals over %d runs", report.Attempts, report.OracleCalls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	items := []int{3, 14, 15, 92, 65, 35, 89, 79}
+	tests := []struct {
+		name      string
+		predicate interface{}
+		want      int
+	}{
+		{"function", func(x int) bool { return x == 92 }, 3},
+		{"range", func(x int) bool { return x > 80 && x < 90 }, 6},
+		{"expression", expr.Item().Between(60, 70), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Search(items, tt.predicate, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 || results[0].Index != tt.want {
+				t.Errorf("Search() = %v, want index %d", results, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchInterfaceItems(t *testing.T) {
+	items := []interface{}{"three", 14, nil, 92.0, 92, fmt.Stringer(nil), 65}
+
+	results, err := Search(items, func(x int) bool { return x == 92 }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Index != 4 {
+		t.Errorf("Search() = %v, want index 4", results)
+	}
+
+	// Items that hold no value of the input type never match
+	if _, err := Search(items, func(s fmt.Stringer) bool { return true }, nil); !errors.Is(err, easyq.ErrNoMatches) {
+		t.Errorf("Search() error = %v, want ErrNoMatches", err)
+	}
+}
+
+func TestValidateInputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     interface{}
+		predicate interface{}
+		wantErr   bool
+	}{
+		{"matching types", []int{1}, func(int) bool { return true }, false},
+		{"array", [2]string{}, func(string) bool { return true }, false},
+		{"interface predicate", []*bytes.Buffer{}, func(fmt.Stringer) bool { return true }, false},
+		{"interface items", []interface{}{}, func(int) bool { return true }, false},
+		{"interface items of another interface", []fmt.Stringer{}, func(*bytes.Buffer) bool { return true }, false},
+		{"expression", []int{1}, expr.Item().Eq(1), false},
+		{"mismatched types", []int{1}, func(string) bool { return true }, true},
+		{"narrower interface items", []interface{}{}, func(fmt.Stringer) bool { return true }, false},
+		{"item type does not implement interface items", []fmt.Stringer{}, func(int) bool { return true }, true},
+		{"interface predicate not implemented", []int{1}, func(fmt.Stringer) bool { return true }, true},
+		{"not a slice", 42, func(int) bool { return true }, true},
+		{"nil items", nil, func(int) bool { return true }, true},
+		{"not a function", []int{1}, "x == 1", true},
+		{"two arguments", []int{1}, func(int, int) bool { return true }, true},
+		{"not a bool", []int{1}, func(int) int { return 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateInputs(tt.items, tt.predicate); (err != nil) != tt.wantErr {
+				t.Errorf("validateInputs() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
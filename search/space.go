@@ -0,0 +1,138 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/search/expr"
+	"github.com/Henrikarba/easyq-go/search/oracle"
+)
+
+// SearchSpace performs a quantum search over the implicit domain of nbits-bit
+// integers, [0, 2^nbits), for values accepted by the predicate. Unlike Search,
+// the domain is never allocated or sent to the backend. The predicate is
+// either a func(uint64) bool or an expr.Predicate over expr.Item(). Options
+// may be nil, in which case default options are used.
+//
+// Each result holds the value found as both its Item and its Index. Only
+// backends running in the same process can evaluate a Go predicate; others
+// fail with a bridge error. On backends other than the simulator, a
+// declarative predicate is compiled into a phase-oracle circuit over the
+// nbits-qubit register (see the oracle package) and sent as the Oracle of a
+// search with no items, so it must be one oracle.Compile supports.
+//
+// Example:
+//
+//	// Find a 16-bit preimage
+//	results, err := search.SearchSpace(16, func(x uint64) bool { return hash(x) == target }, nil)
+//
+//	// Find the multiples of 8 between 1000 and 2000 on any backend
+//	results, err = search.SearchSpace(12, expr.And(
+//		expr.Item().Between(1000, 2000),
+//		expr.Item().Masked(7, 0),
+//	), nil)
+func SearchSpace(nbits int, predicate interface{}, options *easyq.SearchOptions) ([]Result[uint64], error) {
+	return SearchSpaceContext(context.Background(), nbits, predicate, options)
+}
+
+// SearchSpaceContext is like SearchSpace but honours the deadline and cancellation of ctx.
+func SearchSpaceContext(ctx context.Context, nbits int, predicate interface{}, options *easyq.SearchOptions) ([]Result[uint64], error) {
+	results, _, err := SearchSpaceWithReportContext(ctx, nbits, predicate, options)
+	return results, err
+}
+
+// SearchSpaceWithReport is like SearchSpace but also returns a report with
+// statistics about the search. The report is also returned with ErrNoMatches.
+func SearchSpaceWithReport(nbits int, predicate interface{}, options *easyq.SearchOptions) ([]Result[uint64], *easyq.SearchReport, error) {
+	return SearchSpaceWithReportContext(context.Background(), nbits, predicate, options)
+}
+
+// SearchSpaceWithReportContext is like SearchSpaceWithReport but honours the
+// deadline and cancellation of ctx.
+func SearchSpaceWithReportContext(ctx context.Context, nbits int, predicate interface{}, options *easyq.SearchOptions) ([]Result[uint64], *easyq.SearchReport, error) {
+	if nbits <= 0 || nbits > oracle.MaxQubits {
+		return nil, nil, fmt.Errorf("nbits must be between 1 and %d", oracle.MaxQubits)
+	}
+	if predicate == nil {
+		return nil, nil, errors.New("predicate cannot be nil")
+	}
+	start := time.Now()
+
+	var matches func(uint64) bool
+	expression, isExpression := asExpression(predicate)
+	if isExpression {
+		match, err := expr.Compile[uint64](expression)
+		if err != nil {
+			return nil, nil, err
+		}
+		matches = match
+	} else if fn, ok := predicate.(func(uint64) bool); ok && fn != nil {
+		matches = fn
+	} else {
+		return nil, nil, fmt.Errorf("predicate must be a func(uint64) bool or an expr.Predicate, got %T", predicate)
+	}
+
+	// Resolve the session to run on, initializing if necessary
+	session, err := easyq.SessionFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := session.Client()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Use default options if none provided
+	opts := DefaultOptions()
+	if options != nil {
+		opts = *options
+	}
+
+	// Perform the search through the bridge. Quantum hardware needs the
+	// oracle as a circuit, which only a declarative predicate can be compiled to.
+	run := func() ([]interface{}, map[string]interface{}, error) {
+		return client.SearchSpace(ctx, nbits, matches, opts)
+	}
+	var circuit *oracle.Circuit
+	if isExpression && session.Config().BackendType != easyq.Simulator {
+		circuit, err = oracle.Compile(expression, nbits)
+		if err != nil {
+			return nil, nil, err
+		}
+		if opts.EnableLogging {
+			log.Printf("easyq search: compiled %s", circuit)
+		}
+		mapped := map[string]interface{}{
+			"Type":       "Expression",
+			"InputType":  "uint64",
+			"ReturnType": "bool",
+			"Expression": expression,
+			"Oracle":     circuit,
+		}
+		run = func() ([]interface{}, map[string]interface{}, error) {
+			return client.SearchWithReport(ctx, nil, mapped, opts)
+		}
+	}
+
+	verify := opts.Verify == easyq.VerifyAlways || (opts.Verify == easyq.VerifyAuto && !isExpression)
+	raw, report, err := runSearch(ctx, start, opts, run, verify, 1<<nbits, func(i int) bool {
+		return matches(uint64(i))
+	})
+	if circuit != nil && report != nil {
+		setOracleStats(report, circuit)
+	}
+	if err != nil {
+		return nil, report, err
+	}
+
+	results := make([]Result[uint64], 0, len(raw))
+	for _, r := range raw {
+		results = append(results, Result[uint64]{Item: uint64(r.Index), Index: r.Index, Probability: r.Probability})
+	}
+
+	return results, report, nil
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"errors"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+	"github.com/Henrikarba/easyq-go/bridge"
+	"github.com/Henrikarba/easyq-go/search/expr"
+	"github.com/Henrikarba/easyq-go/search/oracle"
+	"github.com/Henrikarba/easyq-go/simulator"
+)
+
+func TestSearchSpace(t *testing.T) {
+	tests := []struct {
+		name      string
+		nbits     int
+		predicate interface{}
+		matches   func(uint64) bool
+	}{
+		{"function", 8, func(x uint64) bool { return x == 201 }, func(x uint64) bool { return x == 201 }},
+		{"expression", 12, expr.And(expr.Item().Between(1000, 2000), expr.Item().Masked(7, 0)),
+			func(x uint64) bool { return x >= 1000 && x <= 2000 && x%8 == 0 }},
+		{"two bits", 2, func(x uint64) bool { return x == 3 }, func(x uint64) bool { return x == 3 }},
+	}
+
+	// Sampling may miss rare matches, which would make the test flaky
+	opts := DefaultOptions()
+	opts.SamplingStrategy = easyq.FullScan
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, report, err := SearchSpaceWithReport(tt.nbits, tt.predicate, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) == 0 {
+				t.Fatal("no results")
+			}
+			for _, result := range results {
+				if !tt.matches(result.Item) || result.Index != int(result.Item) {
+					t.Errorf("result %+v does not match", result)
+				}
+			}
+			if report.Qubits != tt.nbits {
+				t.Errorf("report.Qubits = %d, want %d", report.Qubits, tt.nbits)
+			}
+		})
+	}
+}
+
+func TestSearchSpaceNoMatches(t *testing.T) {
+	never := func(uint64) bool { return false }
+
+	results, report, err := SearchSpaceWithReport(10, never, nil)
+	if !errors.Is(err, easyq.ErrNoMatches) {
+		t.Fatalf("error = %v, want ErrNoMatches", err)
+	}
+	if len(results) != 0 || report == nil {
+		t.Errorf("got %d results and report %v, want no results and a report", len(results), report)
+	}
+
+	opts := DefaultOptions()
+	opts.IterationStrategy = easyq.Exponential
+	_, report, err = SearchSpaceWithReport(10, expr.Item().Gt(5000), &opts)
+	if !errors.Is(err, easyq.ErrNoMatches) {
+		t.Fatalf("Exponential: error = %v, want ErrNoMatches", err)
+	}
+	if report == nil || report.IterationStrategy != easyq.Exponential || report.Attempts == 0 {
+		t.Errorf("Exponential: report = %+v, want the attempts of an exponential search", report)
+	}
+}
+
+func TestSearchSpaceInvalidInputs(t *testing.T) {
+	always := func(uint64) bool { return true }
+
+	tests := []struct {
+		name      string
+		nbits     int
+		predicate interface{}
+	}{
+		{"zero width", 0, always},
+		{"width above oracle.MaxQubits", oracle.MaxQubits + 1, always},
+		{"nil predicate", 8, nil},
+		{"wrong function type", 8, func(int) bool { return true }},
+		{"nil function", 8, (func(uint64) bool)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SearchSpace(tt.nbits, tt.predicate, nil); err == nil {
+				t.Error("SearchSpace succeeded, want an error")
+			}
+		})
+	}
+
+	// The simulator rejects registers wider than it can simulate
+	if _, err := SearchSpace(simulator.DefaultMaxQubits+1, always, nil); !errors.Is(err, bridge.ErrInvalidArgument) {
+		t.Errorf("SearchSpace above the simulator capacity: error = %v, want bridge.ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,210 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+// tuneCandidate is a combination of strategies benchmarked by Tune
+type tuneCandidate struct {
+	iteration  easyq.IterationStrategy
+	sampling   easyq.SamplingStrategy
+	sampleSize int
+}
+
+// Strategies benchmarked by Tune, most commonly useful first
+var (
+	tuneIterations = []easyq.IterationStrategy{
+		easyq.Optimal,
+		easyq.Conservative,
+		easyq.Aggressive,
+		easyq.HalfOptimal,
+		easyq.FixedPoint,
+		easyq.Exponential,
+		easyq.SingleIteration,
+	}
+	tuneSamplings = []tuneCandidate{
+		{sampling: easyq.Auto},
+		{sampling: easyq.FullScan},
+		{sampling: easyq.Sampling, sampleSize: 100},
+		{sampling: easyq.QuantumCounting},
+		{sampling: easyq.Sampling, sampleSize: 30},
+		{sampling: easyq.Sampling, sampleSize: 300},
+		{sampling: easyq.AssumeOne},
+	}
+)
+
+// tuneCandidates returns the candidates benchmarked by Tune, in order: every
+// iteration strategy with Auto sampling, then every sampling strategy with
+// Optimal iterations, then the remaining combinations. Exponential ignores the
+// sampling strategy, so it only runs with Auto.
+func tuneCandidates() []tuneCandidate {
+	var candidates []tuneCandidate
+	for _, iteration := range tuneIterations {
+		candidates = append(candidates, tuneCandidate{iteration: iteration, sampling: easyq.Auto})
+	}
+	for _, sampling := range tuneSamplings[1:] {
+		sampling.iteration = easyq.Optimal
+		candidates = append(candidates, sampling)
+	}
+	for _, iteration := range tuneIterations[1:] {
+		if iteration == easyq.Exponential {
+			continue
+		}
+		for _, sampling := range tuneSamplings[1:] {
+			sampling.iteration = iteration
+			candidates = append(candidates, sampling)
+		}
+	}
+	return candidates
+}
+
+// Tune runs benchmark searches for the predicate over items on the current
+// backend and returns the SearchOptions with the best success probability per
+// predicate evaluation. Evaluations include the oracle calls spent estimating
+// the number of matches and the items FullScan and Sampling test classically,
+// each counted like an oracle call, so a cheap estimate can beat an accurate one.
+// Exponential searches until it finds a match, so it is scored by the oracle
+// calls it takes to find one.
+//
+// budget is the number of benchmark searches to run. Each combination of
+// iteration and sampling strategy is benchmarked at most budget/N times, where
+// N is the number of combinations; with a smaller budget, only the most
+// commonly useful combinations are tried. The predicate must match at least
+// one item, and the backend must report search statistics.
+//
+// Tuned options can be kept for reuse with SaveOptions and LoadOptions.
+//
+// Example:
+//
+//	opts, err := search.Tune(items, predicate, 100)
+//	if err == nil {
+//		err = search.SaveOptions("search-options.json", opts)
+//	}
+func Tune(items interface{}, predicate interface{}, budget int) (easyq.SearchOptions, error) {
+	return TuneContext(context.Background(), items, predicate, budget)
+}
+
+// TuneContext is like Tune but honours the deadline and cancellation of ctx.
+func TuneContext(ctx context.Context, items interface{}, predicate interface{}, budget int) (easyq.SearchOptions, error) {
+	if budget <= 0 {
+		return easyq.SearchOptions{}, errors.New("budget must be greater than zero")
+	}
+
+	itemsValue, sp, err := newSearchPredicate(items, predicate)
+	if err != nil {
+		return easyq.SearchOptions{}, err
+	}
+	size := itemsValue.Len()
+	itemType := itemsValue.Type().Elem()
+
+	// Without matches every strategy scores zero
+	matches := 0
+	for i := 0; i < size; i++ {
+		if sp.matches(i) {
+			matches++
+		}
+	}
+	if matches == 0 {
+		return easyq.SearchOptions{}, easyq.ErrNoMatches
+	}
+
+	// Benchmarks must run on the backend, not a cache
+	ctx = WithCache(ctx, nil)
+
+	candidates := tuneCandidates()
+	candidates = candidates[:min(budget, len(candidates))]
+	repeats := max(budget/len(candidates), 1)
+
+	best := DefaultOptions()
+	bestScore := -1.0
+	for _, candidate := range candidates {
+		opts := DefaultOptions()
+		opts.IterationStrategy = candidate.iteration
+		opts.SamplingStrategy = candidate.sampling
+		if candidate.sampleSize > 0 {
+			opts.SampleSize = candidate.sampleSize
+		}
+
+		// One shot is enough to read the success probability
+		benchmark := opts
+		benchmark.MaxAttempts = 1
+		benchmark.Verify = easyq.VerifyNever
+
+		var score float64
+		for i := 0; i < repeats; i++ {
+			results, report, err := search(ctx, items, size, itemType, sp, &benchmark)
+			if err != nil && !errors.Is(err, easyq.ErrNoMatches) {
+				return easyq.SearchOptions{}, err
+			}
+			if report == nil || report.Qubits == 0 {
+				return easyq.SearchOptions{}, errors.New("backend does not report search statistics")
+			}
+
+			// Exponential ignores MaxAttempts, and its report covers every attempt
+			if candidate.iteration == easyq.Exponential {
+				if len(results) > 0 {
+					score += 1 / float64(max(report.OracleCalls, 1))
+				}
+				continue
+			}
+
+			// Iterations of the search circuit plus the evaluations spent on the estimate
+			calls := report.Iterations + report.OracleCalls - report.Iterations*report.Attempts
+			calls += classicalEvaluations(report.SamplingStrategy, opts.SampleSize, size)
+			score += report.SuccessProbability / float64(max(calls, 1))
+		}
+		score /= float64(repeats)
+
+		if score > bestScore {
+			best, bestScore = opts, score
+		}
+	}
+
+	return best, nil
+}
+
+// classicalEvaluations returns the number of items a sampling strategy tests
+// classically to estimate the number of matches among size items
+func classicalEvaluations(strategy easyq.SamplingStrategy, sampleSize, size int) int {
+	switch strategy {
+	case easyq.FullScan:
+		return size
+	case easyq.Sampling:
+		if sampleSize <= 0 || sampleSize > size {
+			return size
+		}
+		return sampleSize
+	}
+	return 0
+}
+
+// SaveOptions writes options to the file at path as JSON, for reuse with
+// LoadOptions.
+func SaveOptions(path string, options easyq.SearchOptions) error {
+	data, err := json.MarshalIndent(options, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadOptions reads options written by SaveOptions from the file at path.
+// Fields missing from the file keep their default values.
+func LoadOptions(path string) (easyq.SearchOptions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return easyq.SearchOptions{}, err
+	}
+
+	opts := DefaultOptions()
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return easyq.SearchOptions{}, fmt.Errorf("invalid options file %s: %w", path, err)
+	}
+	return opts, nil
+}
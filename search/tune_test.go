@@ -0,0 +1,138 @@
+package search
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	easyq "github.com/Henrikarba/easyq-go"
+)
+
+func TestTuneCandidates(t *testing.T) {
+	candidates := tuneCandidates()
+
+	seen := make(map[tuneCandidate]bool)
+	for i, candidate := range candidates {
+		if seen[candidate] {
+			t.Errorf("candidate %+v benchmarked twice", candidate)
+		}
+		seen[candidate] = true
+
+		if i < len(tuneIterations) && (candidate.iteration != tuneIterations[i] || candidate.sampling != easyq.Auto) {
+			t.Errorf("candidate %d = %+v, want %v with Auto sampling", i, candidate, tuneIterations[i])
+		}
+		if candidate.iteration == easyq.Exponential && candidate.sampling != easyq.Auto {
+			t.Errorf("candidate %+v: Exponential ignores the sampling strategy", candidate)
+		}
+	}
+	if !slices.Contains(tuneIterations, easyq.Exponential) {
+		t.Error("Exponential is not benchmarked")
+	}
+}
+
+func TestTune(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		matches func(int) bool
+	}{
+		{"one rare match", 1024, func(x int) bool { return x == 777 }},
+		{"mostly matches", 256, func(x int) bool { return x%10 != 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]int, tt.size)
+			for i := range items {
+				items[i] = i
+			}
+
+			opts, err := Tune(items, tt.matches, 60)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Contains(tuneCandidates(), tuneCandidate{iteration: opts.IterationStrategy, sampling: opts.SamplingStrategy, sampleSize: sampleSizeOf(opts)}) {
+				t.Errorf("tuned options %+v are not a candidate", opts)
+			}
+
+			// The tuned options find matches
+			opts.MaxAttempts = 10
+			results, err := Search(items, tt.matches, &opts)
+			if err != nil {
+				t.Fatalf("Search with tuned options %+v: %v", opts, err)
+			}
+			for _, result := range results {
+				if !tt.matches(result.Index) {
+					t.Errorf("result %d does not match", result.Index)
+				}
+			}
+		})
+	}
+}
+
+// sampleSizeOf returns the sample size of the Tune candidate that opts were made from
+func sampleSizeOf(opts easyq.SearchOptions) int {
+	if opts.SamplingStrategy != easyq.Sampling {
+		return 0
+	}
+	return opts.SampleSize
+}
+
+func TestTuneInvalidInputs(t *testing.T) {
+	items := []int{1, 2, 3}
+	if _, err := Tune(items, func(x int) bool { return x == 2 }, 0); err == nil {
+		t.Error("Tune with no budget succeeded, want an error")
+	}
+	if _, err := Tune(items, func(x int) bool { return x > 5 }, 10); !errors.Is(err, easyq.ErrNoMatches) {
+		t.Errorf("Tune without matches: error = %v, want ErrNoMatches", err)
+	}
+}
+
+func TestSaveLoadOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "options.json")
+
+	opts := DefaultOptions()
+	opts.IterationStrategy = easyq.Exponential
+	opts.SamplingStrategy = easyq.Sampling
+	opts.SampleSize = 30
+	opts.NoMatchConfidence = 0.999
+	opts.Verify = easyq.VerifyAlways
+	opts.ChunkSize = 4096
+
+	if err := SaveOptions(path, opts); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadOptions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != opts {
+		t.Errorf("LoadOptions() = %+v, want %+v", loaded, opts)
+	}
+
+	// Missing fields keep their defaults
+	if err := os.WriteFile(path, []byte(`{"SampleSize": 7}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = LoadOptions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultOptions()
+	want.SampleSize = 7
+	if loaded != want {
+		t.Errorf("LoadOptions() of a partial file = %+v, want %+v", loaded, want)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOptions(path); err == nil {
+		t.Error("LoadOptions of an invalid file succeeded, want an error")
+	}
+	if _, err := LoadOptions(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadOptions of a missing file: error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,220 @@
+package easyq
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/Henrikarba/easyq-go/bridge"
+)
+
+// Session is a connection to a quantum computing resource that owns its own backend.
+//
+// A Session is safe for concurrent use, and operations on it run concurrently
+// with each other. Separate sessions may use different backends at the same
+// time, for example a simulator and quantum hardware in the same process.
+//
+// Backend types whose registered factory returns the same backend share its
+// connection. This is the case for every hardware type in builds with the
+// easyq_native tag, so at most one hardware configuration can be in use at a
+// time: connecting a session to a different one returns ErrConnectionConflict
+// while another open session is connected through the shared backend.
+//
+// Operations in the search and crypto packages run on the session attached to
+// their context with WithSession, or on the default session otherwise.
+//
+// Example:
+//
+//	hardware, err := easyq.NewSession(easyq.QuantumConnectionConfig{
+//		BackendType: easyq.IBMQuantumExperience,
+//		Token:       "your-api-token",
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer hardware.Close()
+//
+//	ctx := easyq.WithSession(context.Background(), hardware)
+//	key, err := crypto.GenerateKeyContext(ctx, nil)
+type Session struct {
+	mu     sync.RWMutex
+	config QuantumConnectionConfig
+	client *bridge.Client
+	closed bool
+}
+
+var (
+	// openSessions holds every session with a connection, so a session cannot
+	// reconfigure a backend that another one is connected through. It is
+	// guarded by connectMutex, which is held while a session connects.
+	connectMutex sync.Mutex
+	openSessions = make(map[*Session]struct{})
+)
+
+// NewSession creates a session connected to the given quantum computing resource.
+// The session must be closed with Close when no longer needed.
+func NewSession(config QuantumConnectionConfig) (*Session, error) {
+	return NewSessionContext(context.Background(), config)
+}
+
+// NewSessionContext is like NewSession but honours the deadline and
+// cancellation of ctx while connecting.
+func NewSessionContext(ctx context.Context, config QuantumConnectionConfig) (*Session, error) {
+	session := &Session{}
+	if err := session.SetQuantumConnectionContext(ctx, config); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// SetQuantumConnection reconnects the session to a different quantum computing resource.
+// Operations already running finish on the previous backend.
+// Returns ErrSessionClosed if the session has been closed.
+func (s *Session) SetQuantumConnection(config QuantumConnectionConfig) error {
+	return s.SetQuantumConnectionContext(context.Background(), config)
+}
+
+// SetQuantumConnectionContext is like SetQuantumConnection but honours the
+// deadline and cancellation of ctx while connecting.
+func (s *Session) SetQuantumConnectionContext(ctx context.Context, config QuantumConnectionConfig) error {
+	// Validate the configuration
+	if err := validateConnectionConfig(config); err != nil {
+		return err
+	}
+
+	backend, err := newBackend(config.BackendType)
+	if err != nil {
+		return err
+	}
+
+	connectMutex.Lock()
+	if err := s.checkSharedBackend(backend, config); err != nil {
+		connectMutex.Unlock()
+		return err
+	}
+
+	client, err := connect(ctx, backend, config)
+	if err != nil {
+		connectMutex.Unlock()
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		connectMutex.Unlock()
+		client.Shutdown()
+		return ErrSessionClosed
+	}
+	previous := s.client
+	s.client = client
+	s.config = config
+	s.mu.Unlock()
+
+	openSessions[s] = struct{}{}
+	connectMutex.Unlock()
+
+	if previous != nil {
+		previous.Shutdown()
+	}
+	return nil
+}
+
+// checkSharedBackend returns ErrConnectionConflict if another open session is
+// connected through backend with a different configuration.
+// The caller must hold connectMutex.
+func (s *Session) checkSharedBackend(backend bridge.Backend, config QuantumConnectionConfig) error {
+	for other := range openSessions {
+		if other == s {
+			continue
+		}
+
+		other.mu.RLock()
+		conflict := other.client != nil && sameBackend(other.client.Backend(), backend) &&
+			!reflect.DeepEqual(other.config, config)
+		other.mu.RUnlock()
+
+		if conflict {
+			return ErrConnectionConflict
+		}
+	}
+	return nil
+}
+
+// sameBackend reports whether a and b are the same backend instance.
+// Only pointers can be shared, and comparing them never panics.
+func sameBackend(a, b bridge.Backend) bool {
+	t := reflect.TypeOf(a)
+	return t != nil && t == reflect.TypeOf(b) && t.Kind() == reflect.Pointer && a == b
+}
+
+// Config returns the connection configuration of the session.
+func (s *Session) Config() QuantumConnectionConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.config
+}
+
+// Client returns the bridge client the session runs operations on.
+// Returns ErrSessionClosed if the session has been closed.
+func (s *Session) Client() (*bridge.Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, ErrSessionClosed
+	}
+	return s.client, nil
+}
+
+// Close shuts down the session's backend after operations in flight have finished.
+func (s *Session) Close() {
+	s.mu.Lock()
+	client := s.client
+	s.client = nil
+	s.closed = true
+	s.mu.Unlock()
+
+	connectMutex.Lock()
+	delete(openSessions, s)
+	connectMutex.Unlock()
+
+	if client != nil {
+		client.Shutdown()
+	}
+}
+
+// connect creates, initializes and configures a client for backend
+func connect(ctx context.Context, backend bridge.Backend, config QuantumConnectionConfig) (*bridge.Client, error) {
+	client := bridge.NewClient(backend)
+	if err := client.Initialize(); err != nil {
+		return nil, err
+	}
+
+	// Configure the connection through the bridge
+	if err := client.ConfigureConnection(ctx, config); err != nil {
+		client.Shutdown()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+type sessionKey struct{}
+
+// WithSession returns a copy of ctx that makes operations in the search and
+// crypto packages run on the given session.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFromContext returns the session attached to ctx with WithSession.
+// If there is none, it returns the default session, initializing the runtime
+// if necessary.
+func SessionFromContext(ctx context.Context) (*Session, error) {
+	if session, ok := ctx.Value(sessionKey{}).(*Session); ok && session != nil {
+		return session, nil
+	}
+	return DefaultSession()
+}
@@ -0,0 +1,77 @@
+package easyq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Henrikarba/easyq-go/bridge"
+	"github.com/Henrikarba/easyq-go/simulator"
+)
+
+// registerTestBackend registers factory for backendType until the test ends
+func registerTestBackend(t *testing.T, backendType QuantumBackendType, factory BackendFactory) {
+	t.Helper()
+
+	backendsMutex.RLock()
+	previous, ok := backends[backendType]
+	backendsMutex.RUnlock()
+
+	RegisterBackend(backendType, factory)
+	t.Cleanup(func() {
+		if ok {
+			RegisterBackend(backendType, previous)
+			return
+		}
+		backendsMutex.Lock()
+		delete(backends, backendType)
+		backendsMutex.Unlock()
+	})
+}
+
+func TestSessionSharedBackendConflict(t *testing.T) {
+	// Register one backend instance for two hardware types, like the native bridge
+	shared := simulator.NewBackend()
+	factory := func() bridge.Backend { return shared }
+	registerTestBackend(t, IBMQuantumExperience, factory)
+	registerTestBackend(t, GoogleQuantumAI, factory)
+
+	ibm := QuantumConnectionConfig{BackendType: IBMQuantumExperience, Token: "ibm-token"}
+	google := QuantumConnectionConfig{BackendType: GoogleQuantumAI, Token: "google-token"}
+
+	first, err := NewSession(ibm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewSession(google); !errors.Is(err, ErrConnectionConflict) {
+		t.Fatalf("NewSession with a different configuration: error = %v, want ErrConnectionConflict", err)
+	}
+
+	// Sessions with the same configuration and unshared backends are fine
+	second, err := NewSession(ibm)
+	if err != nil {
+		t.Fatalf("NewSession with the same configuration: %v", err)
+	}
+	simulated, err := NewSession(QuantumConnectionConfig{BackendType: Simulator})
+	if err != nil {
+		t.Fatalf("NewSession on the simulator: %v", err)
+	}
+	defer simulated.Close()
+
+	// A session may reconfigure the shared backend once it is the only one using it
+	if err := first.SetQuantumConnection(google); !errors.Is(err, ErrConnectionConflict) {
+		t.Fatalf("SetQuantumConnection with another session open: error = %v, want ErrConnectionConflict", err)
+	}
+	second.Close()
+	if err := first.SetQuantumConnection(google); err != nil {
+		t.Fatalf("SetQuantumConnection as the only session: %v", err)
+	}
+	first.Close()
+
+	third, err := NewSessionContext(context.Background(), ibm)
+	if err != nil {
+		t.Fatalf("NewSession after closing the other sessions: %v", err)
+	}
+	third.Close()
+}
@@ -0,0 +1,336 @@
+package simulator
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+
+	"github.com/Henrikarba/easyq-go/bridge"
+)
+
+// Backend runs EasyQ operations on the state-vector simulator.
+// It implements bridge.Backend and is safe for concurrent use.
+//
+// The zero value is ready to use, so a Backend with a smaller capacity can be
+// created with &simulator.Backend{MaxQubits: 20}.
+type Backend struct {
+	// MaxQubits is the largest register the backend will simulate.
+	// If set to 0, DefaultMaxQubits is used.
+	MaxQubits int
+
+	rngOnce sync.Once
+	rng     *rand.Rand
+}
+
+// NewBackend returns a simulator backend whose measurements are driven by a
+// ChaCha8 generator seeded from crypto/rand.
+func NewBackend() *Backend {
+	return &Backend{}
+}
+
+// random returns the generator driving measurements, seeding it from
+// crypto/rand on first use
+func (b *Backend) random() *rand.Rand {
+	b.rngOnce.Do(func() {
+		var seed [32]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			panic("easyq simulator: failed to seed random source: " + err.Error())
+		}
+		b.rng = rand.New(&lockedSource{src: rand.NewChaCha8(seed)})
+	})
+	return b.rng
+}
+
+// QubitCapacity returns the largest register the backend will simulate.
+// It implements bridge.CapacityReporter.
+func (b *Backend) QubitCapacity() int {
+	return b.maxQubits()
+}
+
+// Initialize prepares the simulator for use. The simulator needs no setup.
+func (b *Backend) Initialize() error {
+	return nil
+}
+
+// Shutdown releases resources held by the simulator. The simulator holds none.
+func (b *Backend) Shutdown() {}
+
+// ConfigureConnection accepts any connection config; the simulator runs locally.
+func (b *Backend) ConfigureConnection(ctx context.Context, config interface{}) error {
+	return nil
+}
+
+// Search performs a quantum search using Grover's algorithm.
+//
+// The predicate must carry the indices of the matching items in a
+// MarkedIndices field, which the simulator encodes as its phase oracle.
+func (b *Backend) Search(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, error) {
+	results, _, err := b.SearchWithReport(ctx, items, predicate, options)
+	return results, err
+}
+
+// SearchWithReport performs a quantum search like Search, and also returns a
+// report with statistics about the run. It implements bridge.SearchReporter.
+func (b *Backend) SearchWithReport(ctx context.Context, items interface{}, predicate interface{}, options interface{}) ([]interface{}, map[string]interface{}, error) {
+	itemsValue := reflect.ValueOf(items)
+	if itemsValue.Kind() != reflect.Slice && itemsValue.Kind() != reflect.Array {
+		return nil, nil, invalidArgument("items must be a slice or array, got %T", items)
+	}
+	size := itemsValue.Len()
+	if size == 0 {
+		return nil, nil, nil
+	}
+
+	marked, err := markedIndices(predicate, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts searchOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, nil, err
+	}
+
+	run, err := groverSearch(ctx, b.random(), b.maxQubits(), size, marked, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, report := searchDocuments(run, func(index int) interface{} {
+		return itemsValue.Index(index).Interface()
+	})
+	return results, report, nil
+}
+
+// SearchSpace performs a quantum search over the integers [0, 2^qubits) for
+// values accepted by oracle. The oracle is evaluated once for every value to
+// build the phase oracle, but the values are never materialized as items.
+// It implements bridge.SpaceSearcher.
+func (b *Backend) SearchSpace(ctx context.Context, qubits int, oracle func(uint64) bool, options interface{}) ([]interface{}, map[string]interface{}, error) {
+	if oracle == nil {
+		return nil, nil, invalidArgument("oracle must not be nil")
+	}
+	if qubits <= 0 || qubits > b.maxQubits() {
+		return nil, nil, invalidArgument("search space of %d qubits out of range [1, %d]", qubits, b.maxQubits())
+	}
+
+	var opts searchOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, nil, err
+	}
+
+	size := 1 << qubits
+	marked := make([]bool, size)
+	for value := 0; value < size; value++ {
+		if value%oracleCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+		}
+		marked[value] = oracle(uint64(value))
+	}
+
+	run, err := groverSearch(ctx, b.random(), b.maxQubits(), size, marked, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, report := searchDocuments(run, func(index int) interface{} {
+		return float64(index)
+	})
+	return results, report, nil
+}
+
+// oracleCheckInterval is how many values SearchSpace evaluates between checks
+// of the context
+const oracleCheckInterval = 4096
+
+// searchDocuments returns the result and report documents of a search run,
+// where item returns the item at an index
+func searchDocuments(run *searchRun, item func(index int) interface{}) ([]interface{}, map[string]interface{}) {
+	results := make([]interface{}, 0, len(run.found))
+	for i, index := range run.found {
+		results = append(results, map[string]interface{}{
+			"Index":       float64(index),
+			"Item":        item(index),
+			"Probability": run.probabilities[i],
+		})
+	}
+
+	report := map[string]interface{}{
+		"IterationStrategy":  float64(run.iterationStrategy),
+		"SamplingStrategy":   float64(run.samplingStrategy),
+		"EstimatedMatches":   float64(run.matches),
+		"Iterations":         float64(run.iterations),
+		"Attempts":           float64(run.attempts),
+		"MaxAttempts":        float64(run.maxAttempts),
+		"OracleCalls":        float64(run.oracleCalls),
+		"Qubits":             float64(run.qubits),
+		"SuccessProbability": run.successProbability,
+	}
+	return results, report
+}
+
+// Count estimates the number of items matching the predicate by quantum
+// counting. The predicate must carry MarkedIndices, as for Search.
+// It implements bridge.Counter.
+func (b *Backend) Count(ctx context.Context, items interface{}, predicate interface{}, options interface{}) (map[string]interface{}, error) {
+	itemsValue := reflect.ValueOf(items)
+	if itemsValue.Kind() != reflect.Slice && itemsValue.Kind() != reflect.Array {
+		return nil, invalidArgument("items must be a slice or array, got %T", items)
+	}
+	size := itemsValue.Len()
+	if size == 0 {
+		return nil, invalidArgument("cannot count matches among zero items")
+	}
+
+	marked, err := markedIndices(predicate, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts countOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+
+	qubits := qubitsFor(size)
+	if qubits > b.maxQubits() {
+		return nil, invalidArgument("search space of %d items needs %d qubits, simulator limit is %d", size, qubits, b.maxQubits())
+	}
+
+	precision := opts.PrecisionBits
+	if precision <= 0 {
+		precision = defaultCountingPrecision(qubits)
+	}
+
+	run, err := quantumCount(ctx, b.random(), size, selectTargets(b.random(), marked, opts.MaxTargets), precision)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.EnableLogging {
+		log.Printf("easyq simulator: quantum counting over %d items with %d precision qubits estimated %.1f matches",
+			size, precision, run.estimate)
+	}
+
+	return map[string]interface{}{
+		"Estimate":      run.estimate,
+		"Lower":         run.lower,
+		"Upper":         run.upper,
+		"Confidence":    run.confidence,
+		"PrecisionBits": float64(run.precisionBits),
+		"Qubits":        float64(run.qubits),
+		"OracleCalls":   float64(run.oracleCalls),
+	}, nil
+}
+
+// GenerateRandomInt generates a random integer between min and max (inclusive).
+func (b *Backend) GenerateRandomInt(ctx context.Context, min, max int) (int, error) {
+	return randomInt(ctx, b.random(), min, max)
+}
+
+// GenerateRandomBytes generates length random bytes.
+func (b *Backend) GenerateRandomBytes(ctx context.Context, length int) ([]byte, error) {
+	return randomBytes(ctx, b.random(), length)
+}
+
+// GenerateKey generates a key using the E91 quantum key distribution protocol.
+func (b *Backend) GenerateKey(ctx context.Context, options interface{}) (map[string]interface{}, error) {
+	var opts keyOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+
+	result, err := generateKey(ctx, b.random(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match the document produced by the native bridge, where byte arrays
+	// are encoded as arrays of numbers.
+	return map[string]interface{}{
+		"Key":                   jsonBytes(result.Key),
+		"Success":               result.Success,
+		"SecurityParameter":     result.SecurityParameter,
+		"ErrorRate":             result.ErrorRate,
+		"EntangledPairsCreated": float64(result.EntangledPairsCreated),
+		"AuthenticationTag":     jsonBytes(result.AuthenticationTag),
+		"FailureReason":         result.FailureReason,
+	}, nil
+}
+
+func (b *Backend) maxQubits() int {
+	if b.MaxQubits > 0 {
+		return b.MaxQubits
+	}
+	return DefaultMaxQubits
+}
+
+// markedIndices extracts the oracle's marked set from a predicate document
+func markedIndices(predicate interface{}, size int) ([]bool, error) {
+	var doc struct {
+		MarkedIndices *[]int
+	}
+	if err := decodeOptions(predicate, &doc); err != nil {
+		return nil, err
+	}
+	if doc.MarkedIndices == nil {
+		return nil, invalidArgument("predicate has no MarkedIndices; the simulator cannot evaluate it")
+	}
+
+	marked := make([]bool, size)
+	for _, index := range *doc.MarkedIndices {
+		if index < 0 || index >= size {
+			return nil, invalidArgument("marked index %d out of range [0, %d)", index, size)
+		}
+		marked[index] = true
+	}
+	return marked, nil
+}
+
+// decodeOptions converts a JSON-serializable value into the given struct
+func decodeOptions(value interface{}, out interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return &bridge.Error{Code: bridge.StatusErrorInvalidArgument, Message: "failed to marshal options", Err: err}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return &bridge.Error{Code: bridge.StatusErrorInvalidArgument, Message: "failed to unmarshal options", Err: err}
+	}
+	return nil
+}
+
+// jsonBytes converts a byte slice to the form of a decoded JSON number array
+func jsonBytes(data []byte) []interface{} {
+	if data == nil {
+		return nil
+	}
+	values := make([]interface{}, len(data))
+	for i, v := range data {
+		values[i] = float64(v)
+	}
+	return values
+}
+
+// invalidArgument returns a bridge error with StatusErrorInvalidArgument
+func invalidArgument(format string, args ...interface{}) error {
+	return bridge.NewError("", bridge.StatusErrorInvalidArgument, fmt.Sprintf(format, args...))
+}
+
+// lockedSource makes a rand.Source safe for concurrent use
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
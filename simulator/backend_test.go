@@ -0,0 +1,28 @@
+package simulator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBackendZeroValue(t *testing.T) {
+	b := &Backend{MaxQubits: 4}
+	if got := b.QubitCapacity(); got != 4 {
+		t.Errorf("QubitCapacity() = %d, want 4", got)
+	}
+
+	value, err := b.GenerateRandomInt(context.Background(), 1, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value < 1 || value > 6 {
+		t.Errorf("GenerateRandomInt(1, 6) = %d, out of range", value)
+	}
+
+	// 32 items need 5 qubits, above the capacity
+	items := make([]interface{}, 32)
+	predicate := map[string]interface{}{"MarkedIndices": []int{3}}
+	if _, err := b.Search(context.Background(), items, predicate, nil); err == nil {
+		t.Error("Search over 32 items succeeded with a 4-qubit capacity")
+	}
+}
@@ -0,0 +1,55 @@
+package simulator
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestQuantumCountBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		matches   int
+		precision int
+	}{
+		{"none", 256, 0, 8},
+		{"one", 256, 1, 8},
+		{"few", 256, 17, 8},
+		{"half", 1000, 500, 10},
+		{"most", 1000, 900, 10},
+		{"all", 64, 64, 6},
+		{"low precision", 1024, 100, 4},
+	}
+
+	const seeds = 50
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marked := make([]bool, tt.size)
+			for i := range tt.matches {
+				marked[i*tt.size/max(tt.matches, 1)] = true
+			}
+
+			covered := 0
+			for seed := range uint64(seeds) {
+				rng := rand.New(rand.NewPCG(seed, seed+1))
+				run, err := quantumCount(context.Background(), rng, tt.size, marked, tt.precision)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if run.lower > run.estimate || run.estimate > run.upper {
+					t.Fatalf("seed %d: estimate %.1f outside its bounds [%.0f, %.0f]", seed, run.estimate, run.lower, run.upper)
+				}
+				if run.lower <= float64(tt.matches) && float64(tt.matches) <= run.upper {
+					covered++
+				}
+			}
+
+			// The bounds hold with probability confidence
+			if float64(covered) < countingConfidence*seeds {
+				t.Errorf("bounds contain the true count %d for %d of %d seeds, want at least %.0f%%",
+					tt.matches, covered, seeds, countingConfidence*100)
+			}
+		})
+	}
+}
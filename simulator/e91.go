@@ -0,0 +1,245 @@
+package simulator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+)
+
+// Authentication modes as defined by easyq.AuthenticationMode
+const (
+	authNone = iota
+	authStandard
+	authEnhanced
+)
+
+// keyOptions mirrors the fields of easyq.KeyDistributionOptions used by the simulator
+type keyOptions struct {
+	KeyLength              int
+	SecurityLevel          int
+	SecurityThreshold      float64
+	MaxAttempts            int
+	EnableLogging          bool
+	AuthenticationMode     int
+	PreSharedSecret        []byte
+	EnableErrorCorrection  bool
+	MaxAcceptableErrorRate float64
+}
+
+// keyResult mirrors easyq.KeyDistributionResult
+type keyResult struct {
+	Key                   []byte
+	Success               bool
+	SecurityParameter     float64
+	ErrorRate             float64
+	EntangledPairsCreated int
+	AuthenticationTag     []byte
+	FailureReason         string
+}
+
+// Measurement angles in the x-z plane of the Bloch sphere. Alice and Bob share
+// the angles pi/4 and pi/2, which yield the raw key; the remaining combinations
+// are used for the CHSH test.
+var (
+	aliceAngles = [3]float64{0, math.Pi / 4, math.Pi / 2}
+	bobAngles   = [3]float64{math.Pi / 4, math.Pi / 2, 3 * math.Pi / 4}
+)
+
+// chshSamplesPerLevel is the minimum number of measurements per CHSH
+// correlation term for each security level.
+const chshSamplesPerLevel = 50
+
+// generateKey runs the E91 protocol: it distributes Bell pairs, measures them
+// in randomly chosen bases, estimates the CHSH value from the mismatched bases
+// and keeps the matching ones as the raw key.
+func generateKey(ctx context.Context, rng *rand.Rand, opts keyOptions) (*keyResult, error) {
+	if opts.KeyLength <= 0 {
+		return nil, invalidArgument("invalid key length %d", opts.KeyLength)
+	}
+	if opts.SecurityLevel < 1 || opts.SecurityLevel > 5 {
+		return nil, invalidArgument("invalid security level %d", opts.SecurityLevel)
+	}
+
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = 5
+	}
+
+	result := &keyResult{}
+	for attempt := 1; attempt <= attempts; attempt++ {
+		session, err := runE91(ctx, rng, opts.KeyLength, opts.SecurityLevel*chshSamplesPerLevel)
+		if err != nil {
+			return nil, err
+		}
+
+		result.EntangledPairsCreated += session.pairs
+		result.SecurityParameter = session.chsh()
+		result.ErrorRate = session.errorRate()
+
+		if opts.EnableLogging {
+			log.Printf("easyq simulator: E91 attempt %d: %d pairs, CHSH %.3f, error rate %.3f",
+				attempt, session.pairs, result.SecurityParameter, result.ErrorRate)
+		}
+
+		switch {
+		case result.SecurityParameter < opts.SecurityThreshold:
+			result.FailureReason = fmt.Sprintf("CHSH value %.3f below security threshold %.3f",
+				result.SecurityParameter, opts.SecurityThreshold)
+			continue
+		case result.ErrorRate > opts.MaxAcceptableErrorRate:
+			result.FailureReason = fmt.Sprintf("error rate %.3f exceeds maximum %.3f",
+				result.ErrorRate, opts.MaxAcceptableErrorRate)
+			continue
+		}
+
+		// The simulated channel is noiseless, so Alice's and Bob's raw keys
+		// agree and error correction has nothing to reconcile.
+		result.Key = packBits(session.aliceKey[:opts.KeyLength])
+		result.Success = true
+		result.FailureReason = ""
+
+		tag, err := authenticate(ctx, rng, result, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.AuthenticationTag = tag
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// e91Session holds the measurement record of one run of the protocol
+type e91Session struct {
+	pairs      int
+	aliceKey   []int
+	bobKey     []int
+	sums       [3][3]float64
+	counts     [3][3]int
+	mismatched int
+}
+
+// runE91 measures Bell pairs until at least keyBits key bits and minSamples
+// samples per CHSH term have been collected.
+func runE91(ctx context.Context, rng *rand.Rand, keyBits, minSamples int) (*e91Session, error) {
+	s := &e91Session{}
+	for len(s.aliceKey) < keyBits || !s.hasCHSHSamples(minSamples) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		a, b := rng.IntN(3), rng.IntN(3)
+
+		aliceBit, bobBit, err := measureBellPair(rng, aliceAngles[a], bobAngles[b])
+		if err != nil {
+			return nil, err
+		}
+		s.pairs++
+
+		if aliceAngles[a] == bobAngles[b] {
+			s.aliceKey = append(s.aliceKey, aliceBit)
+			s.bobKey = append(s.bobKey, bobBit)
+			if aliceBit != bobBit {
+				s.mismatched++
+			}
+			continue
+		}
+
+		// Outcomes are mapped to +1/-1 to estimate the correlation E(a, b)
+		correlation := 1.0
+		if aliceBit != bobBit {
+			correlation = -1.0
+		}
+		s.sums[a][b] += correlation
+		s.counts[a][b]++
+	}
+	return s, nil
+}
+
+// measureBellPair prepares |Phi+> and measures each qubit along the given angle
+func measureBellPair(rng *rand.Rand, aliceAngle, bobAngle float64) (int, int, error) {
+	state, err := NewState(2, rng)
+	if err != nil {
+		return 0, 0, err
+	}
+	state.H(0)
+	state.CNOT(0, 1)
+
+	state.Apply(Ry(-aliceAngle), 0)
+	state.Apply(Ry(-bobAngle), 1)
+
+	return state.Measure(0), state.Measure(1), nil
+}
+
+// chshTerms are the (alice, bob) angle indices of E(a,b), E(a,b'), E(a',b), E(a',b')
+var chshTerms = [4][2]int{{0, 0}, {0, 2}, {2, 0}, {2, 2}}
+
+func (s *e91Session) hasCHSHSamples(minSamples int) bool {
+	for _, term := range chshTerms {
+		if s.counts[term[0]][term[1]] < minSamples {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *e91Session) correlation(a, b int) float64 {
+	if s.counts[a][b] == 0 {
+		return 0
+	}
+	return s.sums[a][b] / float64(s.counts[a][b])
+}
+
+// chsh returns S = |E(a,b) - E(a,b') + E(a',b) + E(a',b')|
+func (s *e91Session) chsh() float64 {
+	return math.Abs(s.correlation(0, 0) - s.correlation(0, 2) + s.correlation(2, 0) + s.correlation(2, 2))
+}
+
+func (s *e91Session) errorRate() float64 {
+	if len(s.aliceKey) == 0 {
+		return 0
+	}
+	return float64(s.mismatched) / float64(len(s.aliceKey))
+}
+
+// packBits packs a slice of bits into bytes, most significant bit first
+func packBits(bitValues []int) []byte {
+	packed := make([]byte, (len(bitValues)+7)/8)
+	for i, bit := range bitValues {
+		if bit != 0 {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return packed
+}
+
+// authenticate computes the authentication tag for a generated key
+func authenticate(ctx context.Context, rng *rand.Rand, result *keyResult, opts keyOptions) ([]byte, error) {
+	if opts.AuthenticationMode == authNone {
+		return nil, nil
+	}
+
+	secret := opts.PreSharedSecret
+	if secret == nil {
+		var err error
+		secret, err = randomBytes(ctx, rng, 32)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(result.Key)
+	if opts.AuthenticationMode == authEnhanced {
+		// Bind the tag to the observed channel statistics as well
+		var stats [16]byte
+		binary.BigEndian.PutUint64(stats[:8], math.Float64bits(result.SecurityParameter))
+		binary.BigEndian.PutUint64(stats[8:], math.Float64bits(result.ErrorRate))
+		mac.Write(stats[:])
+	}
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,74 @@
+package simulator
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRunE91(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	session, err := runE91(context.Background(), rng, 256, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bell pairs violate the CHSH inequality maximally, at 2√2
+	if s := session.chsh(); math.Abs(s-2*math.Sqrt2) > 0.15 {
+		t.Errorf("CHSH value = %.3f, want about %.3f", s, 2*math.Sqrt2)
+	}
+	if rate := session.errorRate(); rate != 0 {
+		t.Errorf("error rate = %.3f, want 0 on a noiseless channel", rate)
+	}
+	if len(session.aliceKey) < 256 {
+		t.Errorf("collected %d key bits, want at least 256", len(session.aliceKey))
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	tests := []struct {
+		name string
+		opts keyOptions
+	}{
+		{"no authentication", keyOptions{KeyLength: 128, SecurityLevel: 1, SecurityThreshold: 2, MaxAcceptableErrorRate: 0.1}},
+		{"standard authentication", keyOptions{KeyLength: 256, SecurityLevel: 3, SecurityThreshold: 2.2, MaxAcceptableErrorRate: 0.1, AuthenticationMode: authStandard}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(5, 6))
+			result, err := generateKey(context.Background(), rng, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Success {
+				t.Fatalf("key generation failed: %s", result.FailureReason)
+			}
+			if got := len(result.Key) * 8; got != tt.opts.KeyLength {
+				t.Errorf("key has %d bits, want %d", got, tt.opts.KeyLength)
+			}
+			if result.ErrorRate != 0 {
+				t.Errorf("error rate = %.3f, want 0", result.ErrorRate)
+			}
+			if tt.opts.AuthenticationMode != authNone && len(result.AuthenticationTag) == 0 {
+				t.Error("missing authentication tag")
+			}
+		})
+	}
+}
+
+func TestGenerateKeyInvalidOptions(t *testing.T) {
+	tests := []keyOptions{
+		{KeyLength: 0, SecurityLevel: 1},
+		{KeyLength: 128, SecurityLevel: 0},
+		{KeyLength: 128, SecurityLevel: 6},
+	}
+
+	rng := rand.New(rand.NewPCG(5, 6))
+	for _, opts := range tests {
+		if _, err := generateKey(context.Background(), rng, opts); err == nil {
+			t.Errorf("generateKey(%+v) succeeded, want an error", opts)
+		}
+	}
+}
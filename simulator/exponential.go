@@ -0,0 +1,105 @@
+package simulator
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/rand/v2"
+)
+
+// Exponential search parameters (Boyer, Brassard, Høyer and Tapp)
+const (
+	// exponentialGrowth is the factor by which the iteration bound grows after a
+	// failed attempt. Any factor strictly between 1 and 4/3 keeps the expected
+	// number of iterations in O(sqrt(N/M)).
+	exponentialGrowth = 6.0 / 5.0
+
+	// exponentialFailure bounds the probability that an attempt misses every
+	// match once the iteration bound has reached sqrt(N)
+	exponentialFailure = 3.0 / 4.0
+
+	// defaultNoMatchConfidence is the confidence used when none is requested
+	defaultNoMatchConfidence = 0.99
+)
+
+// exponentialSearch looks for a single marked index among [0, size) without
+// knowing the number of marked indices, using the randomized exponential
+// schedule of Boyer, Brassard, Høyer and Tapp.
+//
+// Each attempt applies j Grover iterations, with j drawn uniformly from the
+// integers below a bound m, and measures once. The bound starts at 1 and grows
+// by exponentialGrowth after each failed attempt, up to sqrt(N). Once m is at
+// least 1/sin(2θ), which sqrt(N) always is, an attempt finds a match with
+// probability at least 1/4. The search gives up after enough such attempts
+// that a match would have been found with probability NoMatchConfidence.
+func exponentialSearch(ctx context.Context, rng *rand.Rand, qubits, size int, targets []bool, opts searchOptions) (*searchRun, error) {
+	confidence := opts.NoMatchConfidence
+	if confidence == 0 {
+		confidence = defaultNoMatchConfidence
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, invalidArgument("no-match confidence %g out of range (0, 1)", confidence)
+	}
+
+	bound := math.Sqrt(float64(uint64(1) << qubits))
+
+	// Attempts while the bound grows, then enough attempts at the full bound
+	// to reach the requested confidence
+	growing := 0
+	for m := 1.0; m < bound; m = math.Min(m*exponentialGrowth, bound) {
+		growing++
+	}
+	saturated := int(math.Ceil(math.Log(1-confidence) / math.Log(exponentialFailure)))
+
+	run := &searchRun{
+		qubits:            qubits,
+		maxAttempts:       growing + saturated,
+		iterationStrategy: opts.IterationStrategy,
+		samplingStrategy:  opts.SamplingStrategy,
+	}
+
+	if opts.EnableLogging {
+		log.Printf("easyq simulator: exponential search over %d items (%d qubits), at most %d attempts for %.2f%% confidence",
+			size, qubits, run.maxAttempts, confidence*100)
+	}
+
+	isTarget := func(index uint64) bool {
+		return index < uint64(size) && targets[index]
+	}
+
+	m := 1.0
+	for run.attempts < run.maxAttempts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		run.attempts++
+		run.iterations = rng.IntN(int(math.Ceil(m)))
+		run.oracleCalls += run.iterations
+
+		state, err := groverState(ctx, rng, qubits, isTarget, run.iterations)
+		if err != nil {
+			return nil, err
+		}
+
+		index := state.Sample()
+		if isTarget(index) {
+			run.found = []int{int(index)}
+			run.probabilities = []float64{state.Probability(index)}
+			run.successProbability = markedProbability(state, size, targets)
+			break
+		}
+		if run.attempts == run.maxAttempts {
+			run.successProbability = markedProbability(state, size, targets)
+		}
+
+		m = math.Min(m*exponentialGrowth, bound)
+	}
+
+	if opts.EnableLogging {
+		log.Printf("easyq simulator: exponential search found %d matches in %d attempts, %d oracle calls",
+			len(run.found), run.attempts, run.oracleCalls)
+	}
+
+	return run, nil
+}
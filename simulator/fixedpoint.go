@@ -0,0 +1,81 @@
+package simulator
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+)
+
+// defaultTargetSuccessProbability is the success probability used when none is requested
+const defaultTargetSuccessProbability = 0.9
+
+// fixedPointStep holds the phases of one generalized Grover iteration
+type fixedPointStep struct {
+	// oracle is the phase applied to the marked indices
+	oracle float64
+
+	// diffusion is the phase applied to the uniform superposition
+	diffusion float64
+}
+
+// fixedPointSchedule returns the iterations of fixed-point amplitude
+// amplification (Yoder, Low and Chuang) that reach success probability target
+// for any fraction of marked indices of at least matches/space.
+//
+// A sequence of L = 2l+1 oracle queries reaches success probability 1-δ²
+// whenever L ≥ log(2/δ)/sqrt(w), where w is the lower bound on the marked
+// fraction. The phases of iteration j are
+//
+//	α_j = -β_(l-j+1) = 2 arccot(tan(2πj/L) sqrt(1-γ²)),  1/γ = T_(1/L)(1/δ)
+//
+// where T is a Chebyshev polynomial. The simulator applies the oracle phase
+// with the opposite sign to the paper, matching its diffusion convention.
+func fixedPointSchedule(matches, space int, target float64) ([]fixedPointStep, error) {
+	if target == 0 {
+		target = defaultTargetSuccessProbability
+	}
+	if target <= 0 || target >= 1 {
+		return nil, invalidArgument("target success probability %g out of range (0, 1)", target)
+	}
+
+	delta := math.Sqrt(1 - target)
+	fraction := math.Min(float64(matches)/float64(space), 1)
+
+	length := int(math.Ceil(math.Log(2/delta) / math.Sqrt(fraction)))
+	if length%2 == 0 {
+		length++
+	}
+	l := (length - 1) / 2
+
+	gammaInverse := math.Cosh(math.Acosh(1/delta) / float64(length))
+	root := math.Sqrt(1 - 1/(gammaInverse*gammaInverse))
+
+	schedule := make([]fixedPointStep, l)
+	for j := 1; j <= l; j++ {
+		alpha := 2 * (math.Pi/2 - math.Atan(math.Tan(2*math.Pi*float64(j)/float64(length))*root))
+		schedule[j-1].diffusion = alpha
+		schedule[l-j].oracle = alpha
+	}
+	return schedule, nil
+}
+
+// fixedPointState prepares the uniform superposition over the given number of
+// qubits and applies the generalized Grover iterations of schedule to it
+func fixedPointState(ctx context.Context, rng *rand.Rand, qubits int, isTarget func(uint64) bool, schedule []fixedPointStep) (*State, error) {
+	state, err := NewState(qubits, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	for q := 0; q < qubits; q++ {
+		state.H(q)
+	}
+	for _, step := range schedule {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		state.PhaseOracle(isTarget, step.oracle)
+		state.PhaseDiffuse(step.diffusion)
+	}
+	return state, nil
+}
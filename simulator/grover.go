@@ -0,0 +1,333 @@
+package simulator
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Iteration and sampling strategy values as defined by easyq.IterationStrategy
+// and easyq.SamplingStrategy. They are decoded from the JSON options document.
+const (
+	iterationOptimal = iota
+	iterationSingle
+	iterationAggressive
+	iterationConservative
+	iterationHalfOptimal
+	iterationCustom
+	iterationExponential
+	iterationFixedPoint
+)
+
+const (
+	samplingAuto = iota
+	samplingFullScan
+	samplingSampling
+	samplingAssumeOne
+	samplingUserProvided
+	samplingQuantumCounting
+)
+
+// searchOptions mirrors the fields of easyq.SearchOptions used by the simulator
+type searchOptions struct {
+	MaxAttempts           int
+	MaxTargets            int
+	IterationStrategy     int
+	SamplingStrategy      int
+	SampleSize            int
+	FullScanThreshold     int
+	CustomIterationFactor float64
+	CustomIterationOffset int
+	EnableLogging         bool
+	KnownMatchCount       int
+	CountingPrecision     int
+	NoMatchConfidence     float64
+
+	TargetSuccessProbability float64
+}
+
+// searchRun describes a completed Grover search
+type searchRun struct {
+	// found holds the distinct marked indices observed, in the order they were measured
+	found []int
+
+	// probabilities holds the probability of measuring each index in found
+	probabilities []float64
+
+	qubits            int
+	matches           int
+	iterations        int
+	attempts          int
+	maxAttempts       int
+	iterationStrategy int
+	samplingStrategy  int
+
+	// oracleCalls is the total number of Grover iterations applied, including
+	// those spent on estimating the number of matches
+	oracleCalls int
+
+	// successProbability is the probability that a shot measures a marked index
+	successProbability float64
+}
+
+// groverSearch runs Grover's algorithm over the indices [0, size).
+// Shots are taken until the estimated number of matches has been found or
+// MaxAttempts shots have been taken.
+func groverSearch(ctx context.Context, rng *rand.Rand, maxQubits, size int, marked []bool, opts searchOptions) (*searchRun, error) {
+	qubits := qubitsFor(size)
+	if qubits > maxQubits {
+		return nil, invalidArgument("search space of %d items needs %d qubits, simulator limit is %d", size, qubits, maxQubits)
+	}
+
+	targets := selectTargets(rng, marked, opts.MaxTargets)
+	if opts.IterationStrategy == iterationExponential {
+		return exponentialSearch(ctx, rng, qubits, size, targets, opts)
+	}
+
+	estimate, err := estimateMatches(ctx, rng, targets, opts)
+	if err != nil {
+		return nil, err
+	}
+	matches := estimate.count
+
+	run := &searchRun{
+		qubits:            qubits,
+		matches:           matches,
+		maxAttempts:       opts.MaxAttempts,
+		iterationStrategy: opts.IterationStrategy,
+		samplingStrategy:  estimate.strategy,
+		oracleCalls:       estimate.oracleCalls,
+	}
+	if run.maxAttempts <= 0 {
+		run.maxAttempts = 5
+	}
+	if matches == 0 {
+		return run, nil
+	}
+
+	space := 1 << qubits
+	var schedule []fixedPointStep
+	if opts.IterationStrategy == iterationFixedPoint {
+		schedule, err = fixedPointSchedule(matches, space, opts.TargetSuccessProbability)
+		if err != nil {
+			return nil, err
+		}
+		run.iterations = len(schedule)
+	} else {
+		run.iterations = groverIterations(matches, space, opts)
+	}
+
+	if opts.EnableLogging {
+		log.Printf("easyq simulator: search over %d items (%d qubits), estimated %d matches, %d iterations, %d attempts",
+			size, qubits, matches, run.iterations, run.maxAttempts)
+	}
+
+	isTarget := func(index uint64) bool {
+		return index < uint64(size) && targets[index]
+	}
+	var state *State
+	if schedule != nil {
+		state, err = fixedPointState(ctx, rng, qubits, isTarget, schedule)
+	} else {
+		state, err = groverState(ctx, rng, qubits, isTarget, run.iterations)
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.successProbability = markedProbability(state, size, targets)
+
+	// Each attempt is an independent shot of the prepared circuit
+	seen := make(map[int]bool)
+	for run.attempts < run.maxAttempts && len(run.found) < matches {
+		run.attempts++
+		index := state.Sample()
+		if !isTarget(index) || seen[int(index)] {
+			continue
+		}
+		seen[int(index)] = true
+		run.found = append(run.found, int(index))
+		run.probabilities = append(run.probabilities, state.Probability(index))
+	}
+
+	if opts.EnableLogging {
+		log.Printf("easyq simulator: search found %d distinct matches in %d attempts", len(run.found), run.attempts)
+	}
+
+	run.oracleCalls += run.iterations * run.attempts
+	return run, nil
+}
+
+// groverState prepares the uniform superposition over the given number of
+// qubits and applies the given number of Grover iterations to it
+func groverState(ctx context.Context, rng *rand.Rand, qubits int, isTarget func(uint64) bool, iterations int) (*State, error) {
+	state, err := NewState(qubits, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	for q := 0; q < qubits; q++ {
+		state.H(q)
+	}
+	for i := 0; i < iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		state.PhaseOracle(isTarget, math.Pi)
+		state.Diffuse()
+	}
+	return state, nil
+}
+
+// markedProbability returns the probability that measuring state gives one of
+// the marked indices among [0, size)
+func markedProbability(state *State, size int, marked []bool) float64 {
+	var probability float64
+	for index := 0; index < size; index++ {
+		if marked[index] {
+			probability += state.Probability(uint64(index))
+		}
+	}
+	return probability
+}
+
+// qubitsFor returns the number of qubits needed to index size items
+func qubitsFor(size int) int {
+	if size <= 2 {
+		return 1
+	}
+	return bits.Len(uint(size - 1))
+}
+
+// selectTargets restricts the marked set to at most maxTargets randomly chosen indices
+func selectTargets(rng *rand.Rand, marked []bool, maxTargets int) []bool {
+	if maxTargets <= 0 {
+		return marked
+	}
+
+	var indices []int
+	for i, m := range marked {
+		if m {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) <= maxTargets {
+		return marked
+	}
+
+	rng.Shuffle(len(indices), func(i, j int) {
+		indices[i], indices[j] = indices[j], indices[i]
+	})
+
+	targets := make([]bool, len(marked))
+	for _, index := range indices[:maxTargets] {
+		targets[index] = true
+	}
+	return targets
+}
+
+// matchEstimate is an estimate of the number of marked items
+type matchEstimate struct {
+	count int
+
+	// strategy is the sampling strategy that was used
+	strategy int
+
+	// oracleCalls is the number of Grover iterations the estimate cost
+	oracleCalls int
+}
+
+// estimateMatches estimates the number of marked items according to the sampling strategy
+func estimateMatches(ctx context.Context, rng *rand.Rand, marked []bool, opts searchOptions) (matchEstimate, error) {
+	if opts.KnownMatchCount > 0 {
+		return matchEstimate{count: opts.KnownMatchCount, strategy: samplingUserProvided}, nil
+	}
+
+	strategy := opts.SamplingStrategy
+	if strategy == samplingAuto {
+		strategy = samplingSampling
+		if len(marked) <= opts.FullScanThreshold {
+			strategy = samplingFullScan
+		}
+	}
+
+	switch strategy {
+	case samplingFullScan:
+		count := 0
+		for _, m := range marked {
+			if m {
+				count++
+			}
+		}
+		return matchEstimate{count: count, strategy: strategy}, nil
+
+	case samplingSampling:
+		sampleSize := opts.SampleSize
+		if sampleSize <= 0 || sampleSize > len(marked) {
+			sampleSize = len(marked)
+		}
+		hits := 0
+		for i := 0; i < sampleSize; i++ {
+			if marked[rng.IntN(len(marked))] {
+				hits++
+			}
+		}
+		// A sample without hits does not prove there are no matches
+		estimate := int(math.Round(float64(hits) / float64(sampleSize) * float64(len(marked))))
+		return matchEstimate{count: max(estimate, 1), strategy: strategy}, nil
+
+	case samplingAssumeOne:
+		return matchEstimate{count: 1, strategy: strategy}, nil
+
+	case samplingQuantumCounting:
+		precision := opts.CountingPrecision
+		if precision <= 0 {
+			precision = defaultCountingPrecision(qubitsFor(len(marked)))
+		}
+		run, err := quantumCount(ctx, rng, len(marked), marked, precision)
+		if err != nil {
+			return matchEstimate{}, err
+		}
+		if opts.EnableLogging {
+			log.Printf("easyq simulator: quantum counting with %d precision qubits estimated %.1f matches (between %.0f and %.0f)",
+				precision, run.estimate, run.lower, run.upper)
+		}
+		// An estimate of zero only bounds the count by the precision
+		count := max(int(math.Round(run.estimate)), 1)
+		return matchEstimate{count: count, strategy: strategy, oracleCalls: run.oracleCalls}, nil
+
+	case samplingUserProvided:
+		return matchEstimate{strategy: strategy}, invalidArgument("sampling strategy UserProvided requires KnownMatchCount > 0")
+
+	default:
+		return matchEstimate{strategy: strategy}, invalidArgument("unknown sampling strategy %d", opts.SamplingStrategy)
+	}
+}
+
+// groverIterations calculates the number of Grover iterations for the iteration strategy
+func groverIterations(matches, space int, opts searchOptions) int {
+	if matches >= space {
+		return 0
+	}
+
+	angle := math.Asin(math.Sqrt(float64(matches) / float64(space)))
+
+	var iterations float64
+	switch opts.IterationStrategy {
+	case iterationSingle:
+		iterations = 1
+	case iterationAggressive:
+		iterations = math.Pi / (4 * angle)
+	case iterationConservative:
+		iterations = math.Pi/(4*angle) - 1
+	case iterationHalfOptimal:
+		iterations = math.Pi / (8 * angle)
+	case iterationCustom:
+		iterations = (math.Pi/(4*angle)-0.5)*opts.CustomIterationFactor + float64(opts.CustomIterationOffset)
+	default:
+		iterations = math.Pi/(4*angle) - 0.5
+	}
+
+	return max(int(math.Round(iterations)), 0)
+}
@@ -0,0 +1,95 @@
+package simulator
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestGroverSearchHitRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		marked   []int
+		strategy int
+		sampling int
+	}{
+		{"one of 64", 64, []int{37}, iterationOptimal, samplingFullScan},
+		{"one of 1000", 1000, []int{999}, iterationOptimal, samplingFullScan},
+		{"three of 256", 256, []int{3, 100, 200}, iterationOptimal, samplingFullScan},
+		{"conservative", 512, []int{42}, iterationConservative, samplingFullScan},
+		{"exponential", 1024, []int{7}, iterationExponential, samplingAuto},
+		{"fixed point", 256, []int{5, 6}, iterationFixedPoint, samplingFullScan},
+		{"quantum counting", 256, []int{17}, iterationOptimal, samplingQuantumCounting},
+	}
+
+	const trials = 100
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			marked := make([]bool, tt.size)
+			for _, index := range tt.marked {
+				marked[index] = true
+			}
+			opts := searchOptions{
+				IterationStrategy:        tt.strategy,
+				SamplingStrategy:         tt.sampling,
+				TargetSuccessProbability: 0.9,
+			}
+
+			hits := 0
+			for range trials {
+				run, err := groverSearch(context.Background(), rng, DefaultMaxQubits, tt.size, marked, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, index := range run.found {
+					if !marked[index] {
+						t.Fatalf("found unmarked index %d", index)
+					}
+				}
+				if len(run.found) > 0 {
+					hits++
+				}
+			}
+			if hits < trials*95/100 {
+				t.Errorf("found a match in %d of %d searches, want at least 95%%", hits, trials)
+			}
+		})
+	}
+}
+
+func TestGroverSearchNoMatches(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	run, err := groverSearch(context.Background(), rng, DefaultMaxQubits, 128, make([]bool, 128), searchOptions{SamplingStrategy: samplingFullScan})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(run.found) != 0 || run.attempts != 0 {
+		t.Errorf("found %v in %d attempts, want nothing", run.found, run.attempts)
+	}
+}
+
+func TestGroverIterations(t *testing.T) {
+	tests := []struct {
+		matches, space int
+		strategy       int
+		want           int
+	}{
+		{1, 4, iterationOptimal, 1},
+		{1, 1024, iterationOptimal, 25},
+		{1, 1024, iterationAggressive, 25},
+		{1, 1024, iterationConservative, 24},
+		{1, 1024, iterationHalfOptimal, 13},
+		{1, 1024, iterationSingle, 1},
+		{4, 1024, iterationOptimal, 12},
+		{1024, 1024, iterationOptimal, 0},
+	}
+
+	for _, tt := range tests {
+		got := groverIterations(tt.matches, tt.space, searchOptions{IterationStrategy: tt.strategy})
+		if got != tt.want {
+			t.Errorf("groverIterations(%d, %d, strategy %d) = %d, want %d", tt.matches, tt.space, tt.strategy, got, tt.want)
+		}
+	}
+}
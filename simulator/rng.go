@@ -0,0 +1,72 @@
+package simulator
+
+import (
+	"context"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// measureUniform prepares qubits in an equal superposition with Hadamard gates
+// and measures them, yielding a uniformly distributed value in [0, 2^qubits).
+// The qubits are never entangled, so each one is simulated in its own register
+// to keep memory use constant.
+func measureUniform(rng *rand.Rand, qubits int) (uint64, error) {
+	var value uint64
+	for q := 0; q < qubits; q++ {
+		state, err := NewState(1, rng)
+		if err != nil {
+			return 0, err
+		}
+		state.H(0)
+		value |= uint64(state.Measure(0)) << q
+	}
+	return value, nil
+}
+
+// randomInt returns a value in [min, max] using rejection sampling over
+// measurements of the smallest register that covers the range.
+func randomInt(ctx context.Context, rng *rand.Rand, min, max int) (int, error) {
+	if min > max {
+		return 0, invalidArgument("invalid range [%d, %d]", min, max)
+	}
+	if min == max {
+		return min, nil
+	}
+
+	span := uint64(max) - uint64(min)
+	qubits := bits.Len64(span)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		value, err := measureUniform(rng, qubits)
+		if err != nil {
+			return 0, err
+		}
+		if value <= span {
+			return min + int(value), nil
+		}
+	}
+}
+
+// randomBytes fills a buffer of the given length with measured qubit values,
+// eight qubits per byte.
+func randomBytes(ctx context.Context, rng *rand.Rand, length int) ([]byte, error) {
+	if length <= 0 {
+		return nil, invalidArgument("invalid length %d", length)
+	}
+
+	buffer := make([]byte, length)
+	for i := range buffer {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		value, err := measureUniform(rng, 8)
+		if err != nil {
+			return nil, err
+		}
+		buffer[i] = byte(value)
+	}
+	return buffer, nil
+}
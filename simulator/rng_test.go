@@ -0,0 +1,77 @@
+package simulator
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRandomIntBounds(t *testing.T) {
+	tests := []struct {
+		min, max int
+	}{
+		{0, 1},
+		{1, 6},
+		{-10, 10},
+		{5, 5},
+		{0, 1000},
+		{math.MaxInt - 3, math.MaxInt},
+		{math.MinInt, math.MinInt + 2},
+	}
+
+	rng := rand.New(rand.NewPCG(7, 8))
+	for _, tt := range tests {
+		seen := make(map[int]bool)
+		for range 200 {
+			value, err := randomInt(context.Background(), rng, tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("randomInt(%d, %d): %v", tt.min, tt.max, err)
+			}
+			if value < tt.min || value > tt.max {
+				t.Fatalf("randomInt(%d, %d) = %d, out of range", tt.min, tt.max, value)
+			}
+			seen[value] = true
+		}
+
+		// Small ranges should be covered completely
+		if span := uint64(tt.max) - uint64(tt.min); span < 10 && len(seen) != int(span)+1 {
+			t.Errorf("randomInt(%d, %d) produced %d distinct values, want %d", tt.min, tt.max, len(seen), span+1)
+		}
+	}
+}
+
+func TestRandomIntInvalidRange(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	if _, err := randomInt(context.Background(), rng, 2, 1); err == nil {
+		t.Error("randomInt(2, 1) succeeded, want an error")
+	}
+}
+
+func TestRandomBytes(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	buffer, err := randomBytes(context.Background(), rng, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buffer) != 4096 {
+		t.Fatalf("got %d bytes, want 4096", len(buffer))
+	}
+
+	// About half of the bits should be set
+	ones := 0
+	for _, b := range buffer {
+		for ; b != 0; b &= b - 1 {
+			ones++
+		}
+	}
+	if fraction := float64(ones) / (8 * 4096); math.Abs(fraction-0.5) > 0.02 {
+		t.Errorf("fraction of set bits = %.3f, want about 0.5", fraction)
+	}
+
+	for _, length := range []int{0, -1} {
+		if _, err := randomBytes(context.Background(), rng, length); err == nil {
+			t.Errorf("randomBytes(%d) succeeded, want an error", length)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package simulator provides a pure-Go state-vector quantum simulator and a
+// backend built on top of it that needs no native library.
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand/v2"
+)
+
+// DefaultMaxQubits is the default largest register the simulator will allocate.
+// A state of n qubits holds 2^n complex128 amplitudes (16 bytes each).
+const DefaultMaxQubits = 20
+
+// Gate is a single-qubit unitary given as a 2x2 matrix in row-major order.
+type Gate [2][2]complex128
+
+// Standard single-qubit gates
+var (
+	// GateI is the identity gate
+	GateI = Gate{{1, 0}, {0, 1}}
+
+	// GateX is the Pauli-X (NOT) gate
+	GateX = Gate{{0, 1}, {1, 0}}
+
+	// GateY is the Pauli-Y gate
+	GateY = Gate{{0, -1i}, {1i, 0}}
+
+	// GateZ is the Pauli-Z gate
+	GateZ = Gate{{1, 0}, {0, -1}}
+
+	// GateH is the Hadamard gate
+	GateH = Gate{{math.Sqrt2 / 2, math.Sqrt2 / 2}, {math.Sqrt2 / 2, -math.Sqrt2 / 2}}
+
+	// GateS is the phase gate (sqrt(Z))
+	GateS = Gate{{1, 0}, {0, 1i}}
+
+	// GateT is the pi/8 gate (sqrt(S))
+	GateT = Gate{{1, 0}, {0, complex(math.Sqrt2/2, math.Sqrt2/2)}}
+)
+
+// Rx returns a rotation of theta radians around the X axis.
+func Rx(theta float64) Gate {
+	c, s := complex(math.Cos(theta/2), 0), complex(0, -math.Sin(theta/2))
+	return Gate{{c, s}, {s, c}}
+}
+
+// Ry returns a rotation of theta radians around the Y axis.
+func Ry(theta float64) Gate {
+	c, s := complex(math.Cos(theta/2), 0), complex(math.Sin(theta/2), 0)
+	return Gate{{c, -s}, {s, c}}
+}
+
+// Rz returns a rotation of theta radians around the Z axis.
+func Rz(theta float64) Gate {
+	return Gate{{cmplx.Exp(complex(0, -theta/2)), 0}, {0, cmplx.Exp(complex(0, theta/2))}}
+}
+
+// Phase returns the phase shift gate diag(1, e^(i*phi)).
+func Phase(phi float64) Gate {
+	return Gate{{1, 0}, {0, cmplx.Exp(complex(0, phi))}}
+}
+
+// State is the state vector of an n-qubit register.
+// Qubit q corresponds to bit q of a basis state index (little-endian).
+//
+// A State is not safe for concurrent use.
+type State struct {
+	qubits int
+	amps   []complex128
+	rng    *rand.Rand
+}
+
+// NewState returns an n-qubit register initialized to |0...0>.
+// Measurement outcomes are drawn from rng.
+func NewState(qubits int, rng *rand.Rand) (*State, error) {
+	if qubits <= 0 || qubits > 62 {
+		return nil, fmt.Errorf("invalid qubit count %d", qubits)
+	}
+	if rng == nil {
+		return nil, errors.New("rng must not be nil")
+	}
+
+	amps := make([]complex128, 1<<qubits)
+	amps[0] = 1
+
+	return &State{qubits: qubits, amps: amps, rng: rng}, nil
+}
+
+// Qubits returns the number of qubits in the register.
+func (s *State) Qubits() int {
+	return s.qubits
+}
+
+// Amplitude returns the amplitude of the given basis state.
+func (s *State) Amplitude(index uint64) complex128 {
+	return s.amps[index]
+}
+
+// Probability returns the probability of measuring the given basis state.
+func (s *State) Probability(index uint64) float64 {
+	a := s.amps[index]
+	return real(a)*real(a) + imag(a)*imag(a)
+}
+
+// Apply applies a single-qubit gate to qubit q.
+func (s *State) Apply(g Gate, q int) {
+	s.ApplyControlled(g, nil, q)
+}
+
+// ApplyControlled applies a single-qubit gate to the target qubit, conditioned
+// on all control qubits being |1>.
+func (s *State) ApplyControlled(g Gate, controls []int, target int) {
+	var controlMask uint64
+	for _, c := range controls {
+		controlMask |= 1 << c
+	}
+	bit := uint64(1) << target
+
+	for i := range s.amps {
+		index := uint64(i)
+		if index&bit != 0 || index&controlMask != controlMask {
+			continue
+		}
+		a0, a1 := s.amps[index], s.amps[index|bit]
+		s.amps[index] = g[0][0]*a0 + g[0][1]*a1
+		s.amps[index|bit] = g[1][0]*a0 + g[1][1]*a1
+	}
+}
+
+// H applies a Hadamard gate to each of the given qubits.
+func (s *State) H(qubits ...int) {
+	for _, q := range qubits {
+		s.Apply(GateH, q)
+	}
+}
+
+// X applies a Pauli-X gate to each of the given qubits.
+func (s *State) X(qubits ...int) {
+	for _, q := range qubits {
+		s.Apply(GateX, q)
+	}
+}
+
+// Y applies a Pauli-Y gate to each of the given qubits.
+func (s *State) Y(qubits ...int) {
+	for _, q := range qubits {
+		s.Apply(GateY, q)
+	}
+}
+
+// Z applies a Pauli-Z gate to each of the given qubits.
+func (s *State) Z(qubits ...int) {
+	for _, q := range qubits {
+		s.Apply(GateZ, q)
+	}
+}
+
+// CNOT applies a controlled-NOT gate.
+func (s *State) CNOT(control, target int) {
+	s.ApplyControlled(GateX, []int{control}, target)
+}
+
+// CZ applies a controlled-Z gate.
+func (s *State) CZ(control, target int) {
+	s.ApplyControlled(GateZ, []int{control}, target)
+}
+
+// Toffoli applies a doubly-controlled NOT gate.
+func (s *State) Toffoli(control1, control2, target int) {
+	s.ApplyControlled(GateX, []int{control1, control2}, target)
+}
+
+// Swap exchanges the states of two qubits.
+func (s *State) Swap(a, b int) {
+	s.CNOT(a, b)
+	s.CNOT(b, a)
+	s.CNOT(a, b)
+}
+
+// PhaseOracle multiplies the amplitude of every basis state for which
+// marked returns true by e^(i*phi). A phi of pi is the standard Grover oracle.
+func (s *State) PhaseOracle(marked func(index uint64) bool, phi float64) {
+	factor := cmplx.Exp(complex(0, phi))
+	for i := range s.amps {
+		if marked(uint64(i)) {
+			s.amps[i] *= factor
+		}
+	}
+}
+
+// Diffuse applies the Grover diffusion operator 2|s><s| - I over the whole
+// register, where |s> is the uniform superposition.
+func (s *State) Diffuse() {
+	var sum complex128
+	for _, a := range s.amps {
+		sum += a
+	}
+	mean := sum / complex(float64(len(s.amps)), 0)
+	for i, a := range s.amps {
+		s.amps[i] = 2*mean - a
+	}
+}
+
+// PhaseDiffuse applies the generalized diffusion operator
+// (1 - e^(i*alpha))|s><s| - I, where |s> is the uniform superposition.
+// With alpha = pi it is the Grover diffusion operator applied by Diffuse.
+func (s *State) PhaseDiffuse(alpha float64) {
+	var sum complex128
+	for _, a := range s.amps {
+		sum += a
+	}
+	mean := sum / complex(float64(len(s.amps)), 0)
+	factor := 1 - cmplx.Exp(complex(0, alpha))
+	for i, a := range s.amps {
+		s.amps[i] = factor*mean - a
+	}
+}
+
+// Measure measures qubit q in the computational basis, collapsing the state,
+// and returns the outcome (0 or 1).
+func (s *State) Measure(q int) int {
+	bit := uint64(1) << q
+
+	var p1 float64
+	for i := range s.amps {
+		if uint64(i)&bit != 0 {
+			p1 += s.Probability(uint64(i))
+		}
+	}
+
+	outcome := 0
+	if s.rng.Float64() < p1 {
+		outcome = 1
+	}
+
+	norm := p1
+	if outcome == 0 {
+		norm = 1 - p1
+	}
+	scale := complex(1/math.Sqrt(norm), 0)
+	for i := range s.amps {
+		if (uint64(i)&bit != 0) == (outcome == 1) {
+			s.amps[i] *= scale
+		} else {
+			s.amps[i] = 0
+		}
+	}
+
+	return outcome
+}
+
+// MeasureAll measures every qubit, collapsing the state to a basis state,
+// and returns the index of that basis state.
+func (s *State) MeasureAll() uint64 {
+	index := s.Sample()
+	for i := range s.amps {
+		s.amps[i] = 0
+	}
+	s.amps[index] = 1
+	return index
+}
+
+// Sample draws a basis state index from the measurement distribution without
+// collapsing the state. Repeated calls behave like repeated shots of the same
+// circuit.
+func (s *State) Sample() uint64 {
+	r := s.rng.Float64()
+	var cumulative float64
+	for i := range s.amps {
+		cumulative += s.Probability(uint64(i))
+		if r < cumulative {
+			return uint64(i)
+		}
+	}
+	// Guard against rounding errors in the cumulative sum
+	for i := len(s.amps) - 1; i > 0; i-- {
+		if s.Probability(uint64(i)) > 0 {
+			return uint64(i)
+		}
+	}
+	return 0
+}
@@ -1,5 +1,7 @@
 package easyq
 
+import "time"
+
 // QuantumBackendType defines the type of quantum backend to use
 type QuantumBackendType int
 
@@ -23,6 +25,14 @@ const (
 	CustomQuantumBackend
 )
 
+// InitOptions configures the EasyQ runtime at initialization
+type InitOptions struct {
+	// BridgePath is the path of the native EasyQBridge library to load.
+	// If empty, the EASYQ_BRIDGE_PATH environment variable is used, and then the
+	// platform's default library name. Only used in builds with the easyq_native tag.
+	BridgePath string
+}
+
 // QuantumConnectionConfig holds information needed to connect to a quantum computing resource
 type QuantumConnectionConfig struct {
 	// The type of quantum backend to use
@@ -47,6 +57,81 @@ type SearchResult struct {
 
 	// Index is the position of the item in the original collection
 	Index int
+
+	// Key identifies the item in collections without positions, such as the key
+	// of a map entry or the row number of a database row or file record.
+	// It is nil for slices and arrays.
+	Key interface{}
+
+	// Probability is the probability of measuring this item in a single shot
+	// of the search circuit, if reported by the backend
+	Probability float64
+}
+
+// SearchReport holds statistics about a quantum search operation.
+// It is useful for tuning SearchOptions for a workload.
+type SearchReport struct {
+	// IterationStrategy is the iteration strategy used
+	IterationStrategy IterationStrategy
+
+	// SamplingStrategy is the strategy used to estimate the number of matches.
+	// Auto is reported as the strategy it selected, and a KnownMatchCount as UserProvided.
+	SamplingStrategy SamplingStrategy
+
+	// EstimatedMatches is the estimated number of matching items.
+	// It is 0 with the Exponential iteration strategy, which does not estimate it.
+	EstimatedMatches int
+
+	// Iterations is the number of Grover iterations in the search circuit
+	Iterations int
+
+	// Attempts is the number of shots of the search circuit that were taken
+	Attempts int
+
+	// MaxAttempts is the maximum number of attempts that were allowed
+	MaxAttempts int
+
+	// OracleCalls is the total number of oracle applications over all attempts
+	OracleCalls int
+
+	// Qubits is the number of qubits in the search register
+	Qubits int
+
+	// SuccessProbability is the probability that a single shot measures a matching item
+	SuccessProbability float64
+
+	// Duration is the wall time of the search
+	Duration time.Duration
+
+	// Verified reports whether the results were checked against the predicate
+	Verified bool
+
+	// FalsePositives is the number of results that were discarded because they do not
+	// match the predicate
+	FalsePositives int
+
+	// FalsePositiveRate is the fraction of results returned by the backend that were
+	// discarded as false positives
+	FalsePositiveRate float64
+
+	// Retries is the number of times the search was run again because every result
+	// was a false positive. Attempts and OracleCalls are totals over all runs.
+	Retries int
+
+	// Cached reports whether the results were served from a search.Cache.
+	// The other statistics are those of the search that was cached.
+	Cached bool
+
+	// OracleQubits is the number of qubits of the compiled oracle circuit,
+	// including OracleAncillas. The oracle statistics are 0 when the backend
+	// is a simulator, which does not need a circuit.
+	OracleQubits int
+
+	// OracleAncillas is the number of ancilla qubits of the compiled oracle circuit
+	OracleAncillas int
+
+	// OracleGates is the number of gates of the compiled oracle circuit
+	OracleGates int
 }
 
 // SearchOptions configures the behavior of quantum search operations
@@ -87,6 +172,56 @@ type SearchOptions struct {
 	// KnownMatchCount is an optional parameter to specify the exact number of matching items.
 	// If set to 0, will be ignored.
 	KnownMatchCount int
+
+	// CountingPrecision is the number of precision qubits used to estimate the match count.
+	// Only used with SamplingStrategy.QuantumCounting. If set to 0, a default based on
+	// the size of the search space is used.
+	CountingPrecision int
+
+	// NoMatchConfidence is the confidence required before concluding that there are no
+	// matches. Only used with IterationStrategy.Exponential. If set to 0, will use the
+	// default (0.99).
+	NoMatchConfidence float64
+
+	// TargetSuccessProbability is the minimum probability that a single attempt finds a match.
+	// Only used with IterationStrategy.FixedPoint. Higher targets need more iterations.
+	// If set to 0, will use the default (0.9).
+	TargetSuccessProbability float64
+
+	// Verify determines whether results are checked against the predicate before being
+	// returned. False positives are discarded, and the search is run again, up to
+	// MaxAttempts times, if none of the results match.
+	Verify VerifyMode
+
+	// ChunkSize is the number of items searched at a time by FindChunked and FindSeq.
+	// If set to 0, chunks are sized to the qubit capacity of the backend.
+	ChunkSize int
+
+	// Parallelism is the maximum number of chunks searched concurrently by FindChunked
+	// and FindSeq. If set to 0, chunks are searched one at a time.
+	Parallelism int
+}
+
+// CountResult is the result of estimating the number of matching items by quantum counting
+type CountResult struct {
+	// Estimate is the estimated number of matching items
+	Estimate float64
+
+	// Lower and Upper bound the number of matching items with probability Confidence
+	Lower int
+	Upper int
+
+	// Confidence is the probability that the number of matching items is within the bounds
+	Confidence float64
+
+	// PrecisionBits is the number of precision qubits used
+	PrecisionBits int
+
+	// Qubits is the number of qubits in the search register
+	Qubits int
+
+	// OracleCalls is the number of controlled Grover iterations applied
+	OracleCalls int
 }
 
 // IterationStrategy defines strategies for determining the number of Grover iterations
@@ -110,6 +245,20 @@ const (
 
 	// Custom is a custom iteration count using CustomIterationFactor and CustomIterationOffset
 	Custom
+
+	// Exponential finds a match without knowing the number of matches, using the
+	// randomized exponential schedule of Boyer, Brassard, Høyer and Tapp. Each
+	// attempt runs a random number of iterations below a bound that grows until a
+	// match is found or NoMatchConfidence is reached. Only the first match found is
+	// returned, and MaxAttempts and SamplingStrategy are ignored.
+	Exponential
+
+	// FixedPoint uses fixed-point amplitude amplification (Yoder, Low and Chuang),
+	// which cannot overshoot: the success probability is at least
+	// TargetSuccessProbability whenever there are at least as many matches as
+	// estimated. Combine it with AssumeOne, or with a KnownMatchCount that is a lower
+	// bound, when the number of matches is uncertain.
+	FixedPoint
 )
 
 // SamplingStrategy defines strategies for estimating the number of matches in the database
@@ -130,6 +279,24 @@ const (
 
 	// UserProvided uses a specific count provided by the user
 	UserProvided
+
+	// QuantumCounting estimates the count by phase estimation on the Grover operator,
+	// using CountingPrecision qubits of precision
+	QuantumCounting
+)
+
+// VerifyMode defines when search results are checked classically against the predicate
+type VerifyMode int
+
+const (
+	// VerifyAuto verifies results when the predicate is a Go function
+	VerifyAuto VerifyMode = iota
+
+	// VerifyAlways verifies results for every kind of predicate
+	VerifyAlways
+
+	// VerifyNever returns results as measured, without verifying them
+	VerifyNever
 )
 
 // KeyDistributionOptions configures the behavior of quantum key distribution operations
@@ -180,4 +347,26 @@ const (
 	Enhanced
 )
 
-// KeyDistributionResult represents the result of a quantum key distributi
+// KeyDistributionResult represents the result of a quantum key distribution operation
+type KeyDistributionResult struct {
+	// Key is the generated key (if successful)
+	Key []byte
+
+	// Success indicates whether the key distribution was successful
+	Success bool
+
+	// SecurityParameter is the calculated security parameter (CHSH value)
+	SecurityParameter float64
+
+	// ErrorRate is the observed error rate in the raw key
+	ErrorRate float64
+
+	// EntangledPairsCreated is the number of entangled pairs used during key generation
+	EntangledPairsCreated int
+
+	// AuthenticationTag is the authentication tag for key verification
+	AuthenticationTag []byte
+
+	// FailureReason describes why key generation failed (if unsuccessful)
+	FailureReason string
+}